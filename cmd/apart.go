@@ -0,0 +1,83 @@
+// Copyright (c) 2017 Przemysław Dobrowolski
+//
+// This file is part of the math-mod, a package for symbolic manipulation
+// of large algebraic expressions.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/pdobrowo/mm/math"
+	"github.com/spf13/cobra"
+)
+
+var apartWrtFlag *string
+var apartLimitFlags limitFlags
+
+func apartCmdRun(cmd *cobra.Command, args []string) error {
+	if *apartWrtFlag == "" {
+		return fmt.Errorf("missing variable")
+	}
+
+	reader, err := openInput(args)
+
+	if err != nil {
+		return err
+	}
+
+	expr, err := parseInput(reader)
+
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := apartLimitFlags.context()
+	defer cancel()
+
+	pf, err := math.ApartContext(ctx, expr, *apartWrtFlag, apartLimitFlags.limits())
+
+	if err != nil {
+		return apartLimitFlags.explain(err)
+	}
+
+	fmt.Println(pf.Expr().Infix())
+
+	return nil
+}
+
+// apartCmd represents the apart command
+var apartCmd = &cobra.Command{
+	Use:   "apart",
+	Short: "Decompose a rational function into partial fractions",
+	Long: `Decompose a rational function of one variable into
+a polynomial and a sum of partial fractions over Q.
+The denominator is factored into linear and quadratic
+factors where they have rational coefficients.`,
+	RunE: apartCmdRun,
+}
+
+func init() {
+	RootCmd.AddCommand(apartCmd)
+
+	apartWrtFlag = apartCmd.PersistentFlags().String("wrt", "", "Variable of the rational function")
+	apartLimitFlags = addLimitFlags(apartCmd)
+}
@@ -0,0 +1,125 @@
+// Copyright (c) 2017 Przemysław Dobrowolski
+//
+// This file is part of the math-mod, a package for symbolic manipulation
+// of large algebraic expressions.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/pdobrowo/mm/math"
+	"github.com/spf13/cobra"
+)
+
+var degreeWeightsFlag *map[string]int
+var degreeJSONFlag *bool
+var degreeLimitFlags limitFlags
+
+func degreeCmdRun(cmd *cobra.Command, args []string) error {
+	if err := math.CheckWeights(*degreeWeightsFlag); err != nil {
+		return err
+	}
+
+	reader, err := openInput(args)
+
+	if err != nil {
+		return err
+	}
+
+	expr, err := parseInput(reader)
+
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := degreeLimitFlags.context()
+	defer cancel()
+
+	poly, err := math.ExpandContext(ctx, expr, degreeLimitFlags.limits())
+
+	if err != nil {
+		return degreeLimitFlags.explain(err)
+	}
+
+	var weights map[string]int
+
+	if len(*degreeWeightsFlag) > 0 {
+		weights = *degreeWeightsFlag
+	}
+
+	degrees := poly.Degrees(weights)
+
+	if *degreeJSONFlag {
+		data, err := json.Marshal(degrees)
+
+		if err != nil {
+			return err
+		}
+
+		fmt.Println(string(data))
+
+		return nil
+	}
+
+	var names []string
+
+	for name := range degrees.Variables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Println("total degree:", degrees.Total)
+
+	for _, name := range names {
+		fmt.Printf("degree in %v: %d\n", name, degrees.Variables[name])
+	}
+
+	fmt.Println("homogeneous:", degrees.Homogeneous)
+
+	for _, component := range degrees.Components {
+		fmt.Printf("component of degree %d: %d terms\n", component.Degree, component.Terms)
+	}
+
+	return nil
+}
+
+// degreeCmd represents the degree command
+var degreeCmd = &cobra.Command{
+	Use:   "degree",
+	Short: "Report degrees and homogeneity of an expression",
+	Long: `Expand an expression and report its total degree, its
+degree in each variable, whether it is homogeneous and
+the number of terms of its homogeneous components. With
+--weights the degrees are weighted by positive integers,
+variables not given having weight 1.`,
+	RunE: degreeCmdRun,
+}
+
+func init() {
+	RootCmd.AddCommand(degreeCmd)
+
+	degreeWeightsFlag = degreeCmd.PersistentFlags().StringToInt("weights", nil, "Weights of variables, e.g. x=2,y=3")
+	degreeJSONFlag = degreeCmd.PersistentFlags().Bool("json", false, "Print the report in JSON")
+	degreeLimitFlags = addLimitFlags(degreeCmd)
+}
@@ -0,0 +1,141 @@
+// Copyright (c) 2017 Przemysław Dobrowolski
+//
+// This file is part of the math-mod, a package for symbolic manipulation
+// of large algebraic expressions.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/pdobrowo/mm/math"
+	"github.com/spf13/cobra"
+)
+
+var divideByFlag *string
+var divideOrderFlags orderFlags
+var divideWrtFlag *string
+var divideExactFlag *bool
+var divideQuotientsFlag *bool
+var divideLimitFlags limitFlags
+
+func divideCmdRun(cmd *cobra.Command, args []string) error {
+	if *divideByFlag == "" {
+		return fmt.Errorf("missing divisors file")
+	}
+
+	order, err := divideOrderFlags.parse()
+
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := divideLimitFlags.context()
+	defer cancel()
+
+	divisors, err := readPolys(ctx, *divideByFlag, math.Integers, divideLimitFlags.limits())
+
+	if err != nil {
+		return divideLimitFlags.explain(err)
+	}
+
+	if (*divideWrtFlag != "" || *divideExactFlag) && len(divisors) != 1 {
+		return fmt.Errorf("expected a single divisor, got %d", len(divisors))
+	}
+
+	reader, err := openInput(args)
+
+	if err != nil {
+		return err
+	}
+
+	expr, err := parseInput(reader)
+
+	if err != nil {
+		return err
+	}
+
+	poly, err := math.ExpandContext(ctx, expr, divideLimitFlags.limits())
+
+	if err != nil {
+		return divideLimitFlags.explain(err)
+	}
+
+	var quos []math.Poly
+	var rem math.Poly
+
+	switch {
+	case *divideExactFlag:
+		quo, ok, err := math.DivExactContext(ctx, poly, divisors[0], divideLimitFlags.limits())
+		if err != nil {
+			return divideLimitFlags.explain(err)
+		}
+		if !ok {
+			return fmt.Errorf("not divisible")
+		}
+		fmt.Println(quo.ExprIn(order).Infix())
+		return nil
+	case *divideWrtFlag != "":
+		var quo math.Poly
+		quo, rem, err = math.PseudoDivModContext(ctx, poly, divisors[0], *divideWrtFlag, divideLimitFlags.limits())
+		quos = []math.Poly{quo}
+	default:
+		quos, rem, err = math.DivModContext(ctx, poly, divisors, order, divideLimitFlags.limits())
+	}
+
+	if err != nil {
+		return divideLimitFlags.explain(err)
+	}
+
+	if *divideQuotientsFlag {
+		for _, quo := range quos {
+			fmt.Println(quo.ExprIn(order).Infix())
+		}
+	}
+
+	fmt.Println(rem.ExprIn(order).Infix())
+
+	return nil
+}
+
+// divideCmd represents the divide command
+var divideCmd = &cobra.Command{
+	Use:   "divide",
+	Short: "Divide a polynomial with remainder",
+	Long: `Divide an expanded polynomial by the polynomials given
+one per line in a file and print the remainder, which
+is reduced with respect to a monomial order. With --wrt
+the polynomial is pseudo-divided by a single divisor as
+a polynomial in one variable, and with --exact it is
+checked to be a multiple of a single divisor.`,
+	RunE: divideCmdRun,
+}
+
+func init() {
+	RootCmd.AddCommand(divideCmd)
+
+	divideByFlag = divideCmd.PersistentFlags().String("by", "", "File with divisors, one per line")
+	divideOrderFlags = addOrderFlags(divideCmd, "grlex")
+	divideWrtFlag = divideCmd.PersistentFlags().String("wrt", "", "Pseudo-divide as polynomials in the variable")
+	divideExactFlag = divideCmd.PersistentFlags().Bool("exact", false, "Print the exact quotient or fail")
+	divideQuotientsFlag = divideCmd.PersistentFlags().Bool("quotients", false, "Print the quotients, one per line, before the remainder")
+	divideLimitFlags = addLimitFlags(divideCmd)
+}
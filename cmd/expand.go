@@ -0,0 +1,98 @@
+// Copyright (c) 2017 Przemysław Dobrowolski
+//
+// This file is part of the math-mod, a package for symbolic manipulation
+// of large algebraic expressions.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/pdobrowo/mm/math"
+	"github.com/spf13/cobra"
+)
+
+var expandLimitFlags limitFlags
+var expandJobsFlag *int
+var expandModFlag *uint64
+var expandOrderFlags orderFlags
+
+func expandCmdRun(cmd *cobra.Command, args []string) error {
+	reader, err := openInput(args)
+
+	if err != nil {
+		return err
+	}
+
+	expr, err := parseInput(reader)
+
+	if err != nil {
+		return err
+	}
+
+	if *expandJobsFlag < 1 {
+		return fmt.Errorf("invalid number of jobs: %d", *expandJobsFlag)
+	}
+
+	order, err := expandOrderFlags.parse()
+
+	if err != nil {
+		return err
+	}
+
+	ring, err := modRing(*expandModFlag)
+
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := expandLimitFlags.context()
+	defer cancel()
+
+	poly, err := math.ExpandRing(ctx, expr, ring, expandLimitFlags.limits(), *expandJobsFlag)
+
+	if err != nil {
+		return expandLimitFlags.explain(err)
+	}
+
+	fmt.Println(poly.ExprIn(order).Infix())
+
+	return nil
+}
+
+// expandCmd represents the expand command
+var expandCmd = &cobra.Command{
+	Use:   "expand",
+	Short: "Expand an algebraic expression",
+	Long: `Expand an algebraic expression into a sum of monomials
+by multiplying out all products and integer powers
+and collecting like terms.`,
+	RunE: expandCmdRun,
+}
+
+func init() {
+	RootCmd.AddCommand(expandCmd)
+
+	expandLimitFlags = addLimitFlags(expandCmd)
+	expandJobsFlag = expandCmd.PersistentFlags().Int("jobs", 1, "Number of goroutines multiplying large polynomials")
+	expandModFlag = expandCmd.PersistentFlags().Uint64("mod", 0, "Expand with coefficients modulo the given prime")
+	expandOrderFlags = addOrderFlags(expandCmd, "grlex")
+}
@@ -24,7 +24,9 @@
 package cmd
 
 import (
-	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
@@ -34,46 +36,301 @@ import (
 )
 
 var postfixFlag *bool
+var toFlag *string
+var fromFlag *string
+var formatOrderFlags orderFlags
+var dagFlag *bool
+var depthFlag *int
+var resolveFlag *bool
 
 func checkFlags() error {
+	switch *fromFlag {
+	case "infix", "postfix", "prefix", "sexpr", "json", "binary":
+	default:
+		return fmt.Errorf("invalid input format: %v", *fromFlag)
+	}
+
+	switch *toFlag {
+	case "infix", "postfix", "prefix", "sexpr", "json", "binary", "dot", "latex":
+	default:
+		return fmt.Errorf("invalid output format: %v", *toFlag)
+	}
+
+	if (*dagFlag || *depthFlag != 0) && *toFlag != "dot" {
+		return fmt.Errorf("--dag and --depth require --to dot")
+	}
+
+	if *depthFlag < 0 {
+		return fmt.Errorf("invalid depth: %d", *depthFlag)
+	}
+
+	if *formatOrderFlags.order == "" && len(*formatOrderFlags.vars) > 0 {
+		return fmt.Errorf("--vars requires --order")
+	}
+
+	if *postfixFlag == true {
+		if *toFlag != "infix" && *toFlag != "postfix" {
+			return fmt.Errorf("--postfix conflicts with --to %v", *toFlag)
+		}
+
+		*toFlag = "postfix"
+	}
+
 	return nil
 }
 
+// notationParsers read statements in the notations other than infix.
+var notationParsers = map[string]func(io.Reader) ([]math.Statement, error){
+	"postfix": math.ParsePostfixStatements,
+	"prefix":  math.ParsePrefixStatements,
+	"sexpr":   math.ParseSexprStatements,
+}
+
+// readTrees decodes expression trees serialised in the given format: JSON
+// Lines with a document per line, as written by --to json, or a single
+// binary stream.
+func readTrees(reader io.Reader, format string) ([]math.Statement, error) {
+	if format == "binary" {
+		data, err := io.ReadAll(reader)
+
+		if err != nil {
+			return nil, err
+		}
+
+		expr := &math.Expr{}
+
+		if err := expr.UnmarshalBinary(data); err != nil {
+			return nil, fmt.Errorf("failed to decode binary input: %v", err)
+		}
+
+		return []math.Statement{{Expr: expr, Line: 1}}, nil
+	}
+
+	var stmts []math.Statement
+	decoder := json.NewDecoder(reader)
+
+	for {
+		expr := &math.Expr{}
+
+		if err := decoder.Decode(expr); err == io.EOF {
+			return stmts, nil
+		} else if err != nil {
+			return nil, fmt.Errorf("failed to decode json input: document %d: %v", len(stmts)+1, err)
+		}
+
+		stmts = append(stmts, math.Statement{Expr: expr, Line: len(stmts) + 1})
+	}
+}
+
 func formatCmdRun(cmd *cobra.Command, args []string) error {
-	var reader io.Reader
-	var err error
+	if err := checkFlags(); err != nil {
+		return err
+	}
+
+	reader, err := openInput(args)
 
-	if err = checkFlags(); err != nil {
+	if err != nil {
 		return err
 	}
 
-	switch len(args) {
-	case 0:
-		reader = bufio.NewReader(os.Stdin)
-	case 1:
-		reader, err = os.Open(args[0])
+	if *fromFlag == "json" || *fromFlag == "binary" {
+		stmts, err := readTrees(reader, *fromFlag)
 
 		if err != nil {
-			return fmt.Errorf("failed to open file: %v", args[0])
+			return err
 		}
-	default:
-		return fmt.Errorf("invalid number of arguments: %d", len(args))
+
+		return formatStatements(nil, nil, stmts)
 	}
 
-	infix, err := math.ParseInfix(reader)
+	// the text is kept to locate syntax errors in their lines
+	data, err := io.ReadAll(reader)
 
 	if err != nil {
 		return err
 	}
 
-	infix = math.ImplicitOperMul(infix)
+	if parse, ok := notationParsers[*fromFlag]; ok {
+		stmts, err := parse(bytes.NewReader(data))
 
-	if *postfixFlag == true {
-		postfix := math.ToPostfix(infix)
+		if err != nil {
+			return err
+		}
+
+		return formatStatements(data, nil, stmts)
+	}
+
+	name := ""
+	if len(args) == 1 {
+		name = args[0]
+	}
+
+	parser, err := newParser()
+
+	if err != nil {
+		return err
+	}
+
+	// a DAG is read straight into a table, so redundant input is never
+	// built as a tree; sorting and resolving need the tree
+	if *dagFlag && *formatOrderFlags.order == "" && !*resolveFlag {
+		table := math.NewTable()
+		id, err := parser.ParseTable(bytes.NewReader(data), table)
+
+		if err != nil {
+			return err
+		}
+
+		return table.WriteDot(os.Stdout, id, math.DotOptions{MaxDepth: *depthFlag})
+	}
+
+	prog, err := parser.LoadProgram(bytes.NewReader(data), name)
+
+	if err != nil {
+		return err
+	}
 
-		fmt.Println(postfix)
+	return formatStatements(data, prog, prog.Statements)
+}
+
+// formatStatements formats each statement, reporting errors per statement.
+// Names are resolved in the program, if there is one, and syntax errors are
+// located in the lines of the text the statements were read from, if given.
+func formatStatements(data []byte, prog *math.Program, stmts []math.Statement) error {
+	// binary streams are not delimited, so only one could be read back
+	if *toFlag == "binary" && len(stmts) > 1 {
+		return fmt.Errorf("--to binary supports a single statement, got %d", len(stmts))
+	}
+
+	var err error
+	failed := 0
+
+	for _, stmt := range stmts {
+		if stmt.Err == nil && *resolveFlag && prog != nil {
+			stmt, err = resolveStatement(prog, stmt)
+			stmt.Err = err
+		}
+
+		if stmt.Err == nil {
+			stmt.Err = formatStatement(stmt)
+		}
+
+		if stmt.Err != nil {
+			fmt.Fprintln(os.Stderr, statementError(data, stmt))
+			failed++
+		}
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d statements failed", failed, len(stmts))
+	}
+
+	return nil
+}
+
+// statementError describes the error of the statement. Syntax errors are
+// given by the line they occur on and the byte offset within it.
+func statementError(data []byte, stmt math.Statement) string {
+	var parseErr *math.ParseError
+
+	if data != nil && errors.As(stmt.Err, &parseErr) && parseErr.Pos <= len(data) {
+		line := 1 + bytes.Count(data[:parseErr.Pos], []byte("\n"))
+		start := bytes.LastIndexByte(data[:parseErr.Pos], '\n') + 1
+		return fmt.Sprintf("line %d: position %d: %s", line, parseErr.Pos-start, parseErr.Msg)
+	}
+
+	return fmt.Sprintf("line %d: %v", stmt.Line, stmt.Err)
+}
+
+// resolveStatement replaces defined names in the expressions of the
+// statement by their definitions.
+func resolveStatement(prog *math.Program, stmt math.Statement) (math.Statement, error) {
+	var err error
+
+	if stmt.Expr, err = prog.Resolve(stmt.Expr); err != nil {
+		return stmt, err
+	}
+
+	if stmt.Rhs != nil {
+		stmt.Rhs, err = prog.Resolve(stmt.Rhs)
+	}
+
+	return stmt, err
+}
+
+// formatStatement prints the statement in the output format. Assignments and
+// equations are printed in the textual formats only.
+func formatStatement(stmt math.Statement) error {
+	if *formatOrderFlags.order != "" {
+		order, err := formatOrderFlags.parse()
+
+		if err != nil {
+			return err
+		}
+
+		stmt.Expr = stmt.Expr.SortTerms(order)
+
+		if stmt.Rhs != nil {
+			stmt.Rhs = stmt.Rhs.SortTerms(order)
+		}
+	}
+
+	switch *toFlag {
+	case "infix":
+		printStatement(stmt, (*math.Expr).Infix)
+	case "postfix":
+		printStatement(stmt, (*math.Expr).Postfix)
+	case "prefix":
+		printStatement(stmt, (*math.Expr).Prefix)
+	case "sexpr":
+		fmt.Println(stmt.Sexpr())
+	case "latex":
+		fmt.Println(stmt.Latex())
+	default:
+		if stmt.Name != "" || stmt.Rhs != nil {
+			return fmt.Errorf("--to %v supports expressions only", *toFlag)
+		}
+
+		return writeTree(stmt.Expr)
+	}
+
+	return nil
+}
+
+func printStatement(stmt math.Statement, tokens func(*math.Expr) math.Tokens) {
+	switch {
+	case stmt.Name != "":
+		fmt.Println(stmt.Name, ":=", tokens(stmt.Expr))
+	case stmt.Rhs != nil:
+		fmt.Println(tokens(stmt.Expr), "=", tokens(stmt.Rhs))
+	default:
+		fmt.Println(tokens(stmt.Expr))
+	}
+}
+
+// writeTree prints the expression tree in a serialised output format.
+func writeTree(expr *math.Expr) error {
+	if *toFlag == "dot" {
+		return expr.WriteDot(os.Stdout, math.DotOptions{Share: *dagFlag, MaxDepth: *depthFlag})
+	}
+
+	var data []byte
+	var err error
+
+	if *toFlag == "json" {
+		data, err = json.Marshal(expr)
 	} else {
-		fmt.Println(infix)
+		data, err = expr.MarshalBinary()
+	}
+
+	if err != nil {
+		return err
+	}
+
+	os.Stdout.Write(data)
+
+	if *toFlag == "json" {
+		fmt.Println()
 	}
 
 	return nil
@@ -85,7 +342,31 @@ var formatCmd = &cobra.Command{
 	Short: "Format an algebraic expression",
 	Long: `Formatting a large algebraic expression
 is usually a first step to discover its properties
-and possible simplifications.`,
+and possible simplifications. The expression tree can
+be drawn with --to dot for Graphviz and typeset with
+--to latex, variables such as x_1 or a[i,j] getting
+subscripts. With --dag identical subtrees are drawn
+once and a single infix expression is read straight
+into a DAG, never building its tree.
+
+Infix input may hold many statements separated by ';'
+or newlines: expressions, assignments "name := expr" and
+equations "lhs = rhs". Each is formatted on its own
+and errors are reported per statement. Comments run
+from '#' or '//' to the end of line and include "path"
+loads the definitions of another file, relative to the
+including one; --resolve replaces defined names by
+their definitions. Postfix, prefix and sexpr input
+holds the same statements as printed by --to, one
+per line or separated by ';'. Expressions are written
+with --to json one document per line (JSON Lines),
+which --from json reads back, while --to binary takes
+a single expression as binary streams are not
+delimited.
+
+Unlike the commands which expand, format prints the
+terms of sums in the order of the input; --order sorts
+them by a monomial order.`,
 	RunE: formatCmdRun,
 }
 
@@ -93,4 +374,10 @@ func init() {
 	RootCmd.AddCommand(formatCmd)
 
 	postfixFlag = formatCmd.PersistentFlags().Bool("postfix", false, "Use postfix (RPN) format")
+	toFlag = formatCmd.PersistentFlags().String("to", "infix", "Output format: infix, postfix, prefix, sexpr, json, binary, dot or latex")
+	fromFlag = formatCmd.PersistentFlags().String("from", "infix", "Input format: infix, postfix, prefix, sexpr, json or binary")
+	formatOrderFlags = addOrderFlags(formatCmd, "")
+	dagFlag = formatCmd.PersistentFlags().Bool("dag", false, "Draw identical subtrees once in DOT output; infix input is a single expression")
+	depthFlag = formatCmd.PersistentFlags().Int("depth", 0, "Truncate DOT output below the given depth")
+	resolveFlag = formatCmd.PersistentFlags().Bool("resolve", false, "Replace defined names by their definitions")
 }
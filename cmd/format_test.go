@@ -0,0 +1,72 @@
+// Copyright (c) 2017 Przemysław Dobrowolski
+//
+// This file is part of the math-mod, a package for symbolic manipulation
+// of large algebraic expressions.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package cmd
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"bytes"
+	"encoding/json"
+	"strings"
+
+	"github.com/pdobrowo/mm/math"
+)
+
+var _ = Describe("Format Object", func() {
+	Context("when JSON Lines are read", func() {
+		It("should decode a statement per document", func() {
+			var lines []string
+
+			for _, src := range []string{"x + 1", "sin(y) ^ 2"} {
+				expr, err := math.ParseString(src)
+				Expect(err).ShouldNot(HaveOccurred())
+				data, err := json.Marshal(expr)
+				Expect(err).ShouldNot(HaveOccurred())
+				lines = append(lines, string(data))
+			}
+
+			stmts, err := readTrees(strings.NewReader(strings.Join(lines, "\n")+"\n"), "json")
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(stmts).To(HaveLen(2))
+			Expect(stmts[0].Expr.String()).To(Equal("x + 1"))
+			Expect(stmts[1].Expr.String()).To(Equal("sin ( y ) ^ 2"))
+			Expect(stmts[1].Line).To(Equal(2))
+
+			_, err = readTrees(strings.NewReader(lines[0]+"\n{"), "json")
+			Expect(err).To(MatchError("failed to decode json input: document 2: unexpected EOF"))
+		})
+	})
+
+	Context("when a statement fails", func() {
+		It("should locate syntax errors in their line", func() {
+			data := []byte("x + 1\ny + * 2\n")
+			stmts, err := math.ParseStatements(bytes.NewReader(data))
+			Expect(err).ShouldNot(HaveOccurred())
+
+			Expect(statementError(data, stmts[1])).To(Equal("line 2: position 4: missing operand for operator: *"))
+			Expect(statementError(nil, stmts[1])).To(Equal("line 2: position 10: missing operand for operator: *"))
+		})
+	})
+})
@@ -0,0 +1,95 @@
+// Copyright (c) 2017 Przemysław Dobrowolski
+//
+// This file is part of the math-mod, a package for symbolic manipulation
+// of large algebraic expressions.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/pdobrowo/mm/math"
+	"github.com/spf13/cobra"
+)
+
+var groebnerOrderFlags orderFlags
+var groebnerModFlag *uint64
+var groebnerLimitFlags limitFlags
+
+func groebnerCmdRun(cmd *cobra.Command, args []string) error {
+	order, err := groebnerOrderFlags.parse()
+
+	if err != nil {
+		return err
+	}
+
+	ring, err := modRing(*groebnerModFlag)
+
+	if err != nil {
+		return err
+	}
+
+	reader, err := openInput(args)
+
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := groebnerLimitFlags.context()
+	defer cancel()
+
+	polys, err := expandLines(ctx, reader, ring, groebnerLimitFlags.limits(), "input")
+
+	if err != nil {
+		return groebnerLimitFlags.explain(err)
+	}
+
+	basis, err := math.GroebnerContext(ctx, polys, order, groebnerLimitFlags.limits())
+
+	if err != nil {
+		return groebnerLimitFlags.explain(err)
+	}
+
+	for _, poly := range basis {
+		fmt.Println(poly.ExprIn(order).Infix())
+	}
+
+	return nil
+}
+
+// groebnerCmd represents the groebner command
+var groebnerCmd = &cobra.Command{
+	Use:   "groebner",
+	Short: "Compute a Gröbner basis of polynomials",
+	Long: `Compute the reduced Gröbner basis of the ideal generated
+by polynomials given one per line, over Q or modulo
+a prime. Basis polynomials are printed one per line,
+monic and by ascending leading monomials.`,
+	RunE: groebnerCmdRun,
+}
+
+func init() {
+	RootCmd.AddCommand(groebnerCmd)
+
+	groebnerOrderFlags = addOrderFlags(groebnerCmd, "grevlex")
+	groebnerModFlag = groebnerCmd.PersistentFlags().Uint64("mod", 0, "Compute with coefficients modulo the given prime")
+	groebnerLimitFlags = addLimitFlags(groebnerCmd)
+}
@@ -0,0 +1,118 @@
+// Copyright (c) 2017 Przemysław Dobrowolski
+//
+// This file is part of the math-mod, a package for symbolic manipulation
+// of large algebraic expressions.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/pdobrowo/mm/math"
+	"github.com/spf13/cobra"
+)
+
+var reduceBasisFlag *string
+var reduceOrderFlags orderFlags
+var reduceModFlag *uint64
+var reduceLimitFlags limitFlags
+
+func reduceCmdRun(cmd *cobra.Command, args []string) error {
+	if *reduceBasisFlag == "" {
+		return fmt.Errorf("missing basis file")
+	}
+
+	order, err := reduceOrderFlags.parse()
+
+	if err != nil {
+		return err
+	}
+
+	ring, err := modRing(*reduceModFlag)
+
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := reduceLimitFlags.context()
+	defer cancel()
+
+	relations, err := readPolys(ctx, *reduceBasisFlag, ring, reduceLimitFlags.limits())
+
+	if err != nil {
+		return reduceLimitFlags.explain(err)
+	}
+
+	reader, err := openInput(args)
+
+	if err != nil {
+		return err
+	}
+
+	expr, err := parseInput(reader)
+
+	if err != nil {
+		return err
+	}
+
+	basis, err := math.GroebnerContext(ctx, relations, order, reduceLimitFlags.limits())
+
+	if err != nil {
+		return reduceLimitFlags.explain(err)
+	}
+
+	poly, err := math.ExpandRing(ctx, expr, ring, reduceLimitFlags.limits(), 1)
+
+	if err != nil {
+		return reduceLimitFlags.explain(err)
+	}
+
+	poly, err = math.NormalFormContext(ctx, poly, basis, order, reduceLimitFlags.limits())
+
+	if err != nil {
+		return reduceLimitFlags.explain(err)
+	}
+
+	fmt.Println(poly.ExprIn(order).Infix())
+
+	return nil
+}
+
+// reduceCmd represents the reduce command
+var reduceCmd = &cobra.Command{
+	Use:   "reduce",
+	Short: "Reduce an expression modulo polynomial relations",
+	Long: `Expand an expression and reduce it to its normal form
+modulo the ideal generated by polynomial relations given
+one per line. The relations are completed to a Gröbner
+basis first, so expressions equal modulo the relations
+have the same normal form.`,
+	RunE: reduceCmdRun,
+}
+
+func init() {
+	RootCmd.AddCommand(reduceCmd)
+
+	reduceBasisFlag = reduceCmd.PersistentFlags().String("basis", "", "File with relations, one per line")
+	reduceOrderFlags = addOrderFlags(reduceCmd, "grevlex")
+	reduceModFlag = reduceCmd.PersistentFlags().Uint64("mod", 0, "Reduce with coefficients modulo the given prime")
+	reduceLimitFlags = addLimitFlags(reduceCmd)
+}
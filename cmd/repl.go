@@ -0,0 +1,317 @@
+// Copyright (c) 2017 Przemysław Dobrowolski
+//
+// This file is part of the math-mod, a package for symbolic manipulation
+// of large algebraic expressions.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/pdobrowo/mm/math"
+	"github.com/spf13/cobra"
+)
+
+var replLimitFlags limitFlags
+
+const replHelp = `name := expr        bind an expression to a name
+name                print the expression bound to name
+format name         print name in infix notation
+postfix name        print name in postfix (RPN) notation
+expand name         expand name into a sum of monomials
+subst name var expr substitute expr for var in name
+diff name var       differentiate name with respect to var
+undo                revert the last change of a binding
+history             list the commands entered so far
+list                list all bindings
+help                print this help
+quit                leave the session`
+
+// replChange records the value a binding had before a step, so that it can
+// be undone. prev is nil when the name was not bound.
+type replChange struct {
+	name string
+	prev *math.Expr
+}
+
+type replSession struct {
+	out      io.Writer
+	limits   limitFlags
+	bindings map[string]*math.Expr
+	history  []string
+	changes  []replChange
+}
+
+func newReplSession(out io.Writer, limits limitFlags) *replSession {
+	return &replSession{
+		out:      out,
+		limits:   limits,
+		bindings: make(map[string]*math.Expr),
+	}
+}
+
+// parse reads an expression and replaces names bound in the session by
+// their expressions.
+func (session *replSession) parse(src string) (*math.Expr, error) {
+	expr, err := parseInput(strings.NewReader(src))
+
+	if err != nil {
+		return nil, err
+	}
+
+	return expr.Subst(session.bindings), nil
+}
+
+func (session *replSession) lookup(name string) (*math.Expr, error) {
+	expr, ok := session.bindings[name]
+
+	if !ok {
+		return nil, fmt.Errorf("unknown name: %v", name)
+	}
+
+	return expr, nil
+}
+
+func (session *replSession) bind(name string, expr *math.Expr) {
+	session.changes = append(session.changes, replChange{name: name, prev: session.bindings[name]})
+	session.bindings[name] = expr
+
+	fmt.Fprintf(session.out, "%s := %v\n", name, expr)
+}
+
+func (session *replSession) undo() error {
+	if len(session.changes) == 0 {
+		return fmt.Errorf("nothing to undo")
+	}
+
+	change := session.changes[len(session.changes)-1]
+	session.changes = session.changes[:len(session.changes)-1]
+
+	if change.prev == nil {
+		delete(session.bindings, change.name)
+		fmt.Fprintf(session.out, "%s unbound\n", change.name)
+	} else {
+		session.bindings[change.name] = change.prev
+		fmt.Fprintf(session.out, "%s := %v\n", change.name, change.prev)
+	}
+
+	return nil
+}
+
+// step applies a transformation to a bound expression and rebinds the name
+// to the result.
+func (session *replSession) step(name string, transform func(*math.Expr) (*math.Expr, error)) error {
+	expr, err := session.lookup(name)
+
+	if err != nil {
+		return err
+	}
+
+	result, err := transform(expr)
+
+	if err != nil {
+		return err
+	}
+
+	session.bind(name, result)
+	return nil
+}
+
+// expand expands the expression within the limits of the session.
+func (session *replSession) expand(expr *math.Expr) (math.Poly, error) {
+	ctx, cancel := session.limits.context()
+	defer cancel()
+
+	poly, err := math.ExpandContext(ctx, expr, session.limits.limits())
+
+	if err != nil {
+		return math.Poly{}, session.limits.explain(err)
+	}
+
+	return poly, nil
+}
+
+// exec runs a single line of input and records it in the history when it
+// succeeds. It returns io.EOF when the session should end.
+func (session *replSession) exec(line string) error {
+	err := session.run(line)
+
+	if err == nil && line != "" {
+		session.history = append(session.history, line)
+	}
+
+	return err
+}
+
+func (session *replSession) run(line string) error {
+	if i := strings.Index(line, ":="); i >= 0 {
+		name := strings.TrimSpace(line[:i])
+
+		if !isName(name) {
+			return fmt.Errorf("invalid name: %v", name)
+		}
+
+		expr, err := session.parse(line[i+2:])
+
+		if err != nil {
+			return err
+		}
+
+		session.bind(name, expr)
+		return nil
+	}
+
+	fields := strings.Fields(line)
+
+	switch {
+	case len(fields) == 0:
+		return nil
+	case len(fields) == 1 && (fields[0] == "quit" || fields[0] == "exit"):
+		return io.EOF
+	case len(fields) == 1 && fields[0] == "help":
+		fmt.Fprintln(session.out, replHelp)
+	case len(fields) == 1 && fields[0] == "undo":
+		return session.undo()
+	case len(fields) == 1 && fields[0] == "history":
+		for i, entry := range session.history {
+			fmt.Fprintf(session.out, "%4d  %s\n", i+1, entry)
+		}
+	case len(fields) == 1 && fields[0] == "list":
+		var names []string
+		for name := range session.bindings {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			fmt.Fprintf(session.out, "%s := %v\n", name, session.bindings[name])
+		}
+	case len(fields) == 2 && fields[0] == "format":
+		expr, err := session.lookup(fields[1])
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(session.out, expr)
+	case len(fields) == 2 && fields[0] == "postfix":
+		expr, err := session.lookup(fields[1])
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(session.out, expr.Postfix())
+	case len(fields) == 2 && fields[0] == "expand":
+		return session.step(fields[1], func(expr *math.Expr) (*math.Expr, error) {
+			poly, err := session.expand(expr)
+			if err != nil {
+				return nil, err
+			}
+			return poly.Expr(), nil
+		})
+	case len(fields) >= 4 && fields[0] == "subst":
+		value, err := session.parse(strings.Join(fields[3:], " "))
+		if err != nil {
+			return err
+		}
+		return session.step(fields[1], func(expr *math.Expr) (*math.Expr, error) {
+			return expr.Subst(map[string]*math.Expr{fields[2]: value}), nil
+		})
+	case len(fields) == 3 && fields[0] == "diff":
+		return session.step(fields[1], func(expr *math.Expr) (*math.Expr, error) {
+			poly, err := session.expand(expr)
+			if err != nil {
+				return nil, err
+			}
+			return poly.Diff(fields[2]).Expr(), nil
+		})
+	case isName(line):
+		expr, err := session.lookup(line)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(session.out, expr)
+	default:
+		expr, err := session.parse(line)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(session.out, expr)
+	}
+
+	return nil
+}
+
+func isName(name string) bool {
+	if name == "" || name[0] >= '0' && name[0] <= '9' {
+		return false
+	}
+
+	for _, c := range name {
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9') {
+			return false
+		}
+	}
+
+	return true
+}
+
+func replCmdRun(cmd *cobra.Command, args []string) error {
+	session := newReplSession(os.Stdout, replLimitFlags)
+	scanner := bufio.NewScanner(os.Stdin)
+
+	for {
+		fmt.Print("mm> ")
+
+		if !scanner.Scan() {
+			fmt.Println()
+			return scanner.Err()
+		}
+
+		line := strings.TrimSpace(scanner.Text())
+		err := session.exec(line)
+
+		if err == io.EOF {
+			return nil
+		}
+
+		if err != nil {
+			fmt.Printf("error: %v\n", err)
+		}
+	}
+}
+
+// replCmd represents the repl command
+var replCmd = &cobra.Command{
+	Use:   "repl",
+	Short: "Start an interactive session",
+	Long: `Start an interactive session in which expressions can be
+bound to names and transformed step by step, with history
+and undo. Type "help" in the session for a list of commands.`,
+	RunE: replCmdRun,
+}
+
+func init() {
+	RootCmd.AddCommand(replCmd)
+
+	replLimitFlags = addLimitFlags(replCmd)
+}
@@ -0,0 +1,102 @@
+// Copyright (c) 2017 Przemysław Dobrowolski
+//
+// This file is part of the math-mod, a package for symbolic manipulation
+// of large algebraic expressions.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package cmd
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"bytes"
+	"io"
+	"strings"
+)
+
+var _ = Describe("Repl Object", func() {
+	var (
+		out     bytes.Buffer
+		session *replSession
+	)
+
+	BeforeEach(func() {
+		out.Reset()
+		session = newReplSession(&out, replLimitFlags)
+	})
+
+	exec := func(lines ...string) string {
+		out.Reset()
+		for _, line := range lines {
+			Expect(session.exec(line)).To(Succeed())
+		}
+		return strings.TrimSpace(out.String())
+	}
+
+	Context("when expressions are bound", func() {
+		It("should substitute bound names", func() {
+			Expect(exec("p := x + 1", "q := p * p")).To(Equal("p := x + 1\nq := ( x + 1 ) * ( x + 1 )"))
+			Expect(exec("list")).To(Equal("p := x + 1\nq := ( x + 1 ) * ( x + 1 )"))
+		})
+	})
+
+	Context("when a binding is transformed", func() {
+		It("should rebind the name", func() {
+			exec("p := (x + y)^2")
+			Expect(exec("expand p")).To(Equal("p := x ^ 2 + 2 * x * y + y ^ 2"))
+			Expect(exec("subst p y 1")).To(Equal("p := x ^ 2 + 2 * x * 1 + 1 ^ 2"))
+			Expect(exec("diff p x")).To(Equal("p := 2 * x + 2"))
+		})
+	})
+
+	Context("when changes are undone", func() {
+		It("should restore the previous bindings", func() {
+			exec("p := x", "p := y")
+			Expect(exec("undo")).To(Equal("p := x"))
+			Expect(exec("undo")).To(Equal("p unbound"))
+			Expect(session.exec("undo")).To(MatchError("nothing to undo"))
+			Expect(session.exec("p")).To(MatchError("unknown name: p"))
+		})
+	})
+
+	Context("when commands are entered", func() {
+		It("should keep the successful ones in the history", func() {
+			exec("p := x")
+			Expect(session.exec("expand q")).To(MatchError("unknown name: q"))
+			Expect(exec("history")).To(Equal("1  p := x"))
+		})
+	})
+
+	Context("when the input is invalid", func() {
+		It("should report the error", func() {
+			Expect(session.exec("1 := x")).To(MatchError("invalid name: 1"))
+			Expect(session.exec("p := x +")).To(HaveOccurred())
+			Expect(session.exec("diff q x")).To(MatchError("unknown name: q"))
+			Expect(session.bindings).To(BeEmpty())
+		})
+	})
+
+	Context("when the session ends", func() {
+		It("should return EOF", func() {
+			Expect(session.exec("quit")).To(Equal(io.EOF))
+		})
+	})
+})
@@ -0,0 +1,132 @@
+// Copyright (c) 2017 Przemysław Dobrowolski
+//
+// This file is part of the math-mod, a package for symbolic manipulation
+// of large algebraic expressions.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pdobrowo/mm/math"
+	"github.com/spf13/cobra"
+)
+
+var resultantWrtFlag *string
+var resultantMethodFlag *string
+var resultantDiscriminantFlag *bool
+var resultantModFlag *uint64
+var resultantOrderFlags orderFlags
+var resultantLimitFlags limitFlags
+
+// readPoly expands the expression in a file within the limits.
+func readPoly(ctx context.Context, path string, ring math.Ring, limits math.Limits) (math.Poly, error) {
+	expr, err := parseFile(path)
+
+	if err != nil {
+		return math.Poly{}, err
+	}
+
+	return math.ExpandRing(ctx, expr, ring, limits, 1)
+}
+
+func resultantCmdRun(cmd *cobra.Command, args []string) error {
+	if *resultantWrtFlag == "" {
+		return fmt.Errorf("missing variable")
+	}
+
+	ring, err := modRing(*resultantModFlag)
+
+	if err != nil {
+		return err
+	}
+
+	order, err := resultantOrderFlags.parse()
+
+	if err != nil {
+		return err
+	}
+
+	files := 2
+	if *resultantDiscriminantFlag {
+		files = 1
+	}
+
+	if len(args) != files {
+		return fmt.Errorf("invalid number of arguments: %d", len(args))
+	}
+
+	ctx, cancel := resultantLimitFlags.context()
+	defer cancel()
+
+	limits := resultantLimitFlags.limits()
+	polys := make([]math.Poly, len(args))
+
+	for i, arg := range args {
+		if polys[i], err = readPoly(ctx, arg, ring, limits); err != nil {
+			return resultantLimitFlags.explain(err)
+		}
+	}
+
+	var result math.Poly
+
+	switch {
+	case *resultantDiscriminantFlag:
+		result, err = math.DiscriminantContext(ctx, polys[0], *resultantWrtFlag, limits)
+	case *resultantMethodFlag == "subresultant":
+		result, err = math.ResultantContext(ctx, polys[0], polys[1], *resultantWrtFlag, limits)
+	case *resultantMethodFlag == "sylvester":
+		result, err = math.SylvesterResultantContext(ctx, polys[0], polys[1], *resultantWrtFlag, limits)
+	default:
+		return fmt.Errorf("unknown method: %v", *resultantMethodFlag)
+	}
+
+	if err != nil {
+		return resultantLimitFlags.explain(err)
+	}
+
+	fmt.Println(result.ExprIn(order).Infix())
+
+	return nil
+}
+
+// resultantCmd represents the resultant command
+var resultantCmd = &cobra.Command{
+	Use:   "resultant a b",
+	Short: "Eliminate a variable from two polynomials",
+	Long: `Compute the resultant of the polynomials in two files
+with respect to a variable, which vanishes exactly where
+they have a common root. With --discriminant a single
+file is read and its discriminant is computed.`,
+	RunE: resultantCmdRun,
+}
+
+func init() {
+	RootCmd.AddCommand(resultantCmd)
+
+	resultantWrtFlag = resultantCmd.PersistentFlags().String("wrt", "", "Variable to eliminate")
+	resultantMethodFlag = resultantCmd.PersistentFlags().String("method", "subresultant", "Method: subresultant or sylvester")
+	resultantDiscriminantFlag = resultantCmd.PersistentFlags().Bool("discriminant", false, "Compute the discriminant of a single polynomial")
+	resultantModFlag = resultantCmd.PersistentFlags().Uint64("mod", 0, "Compute with coefficients modulo the given prime")
+	resultantOrderFlags = addOrderFlags(resultantCmd, "grlex")
+	resultantLimitFlags = addLimitFlags(resultantCmd)
+}
@@ -0,0 +1,102 @@
+// Copyright (c) 2017 Przemysław Dobrowolski
+//
+// This file is part of the math-mod, a package for symbolic manipulation
+// of large algebraic expressions.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/pdobrowo/mm/math"
+	"github.com/spf13/cobra"
+)
+
+var rewriteRulesFlag *string
+var rewriteStepsFlag *int
+var rewriteLimitFlags limitFlags
+
+func rewriteCmdRun(cmd *cobra.Command, args []string) error {
+	if *rewriteRulesFlag == "" {
+		return fmt.Errorf("missing rules file")
+	}
+
+	file, err := os.Open(*rewriteRulesFlag)
+
+	if err != nil {
+		return fmt.Errorf("failed to open file: %v", *rewriteRulesFlag)
+	}
+
+	defer file.Close()
+
+	rules, err := math.ParseRules(file)
+
+	if err != nil {
+		return fmt.Errorf("%v: %v", *rewriteRulesFlag, err)
+	}
+
+	reader, err := openInput(args)
+
+	if err != nil {
+		return err
+	}
+
+	expr, err := parseInput(reader)
+
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := rewriteLimitFlags.context()
+	defer cancel()
+
+	expr, _, err = math.RewriteContext(ctx, expr, rules, *rewriteStepsFlag, rewriteLimitFlags.limits())
+
+	if err != nil {
+		return rewriteLimitFlags.explain(err)
+	}
+
+	fmt.Println(expr.Infix())
+
+	return nil
+}
+
+// rewriteCmd represents the rewrite command
+var rewriteCmd = &cobra.Command{
+	Use:   "rewrite",
+	Short: "Rewrite an algebraic expression with a set of rules",
+	Long: `Rewrite an algebraic expression by applying rules of the form
+lhs -> rhs, one per line, until none of them matches. Names with
+a leading ?, like ?a, are wildcards, other names match only
+themselves. Sums and products are matched regardless of the
+order and grouping of their operands, and their last wildcard
+operand takes all operands left by the rest of the pattern.`,
+	RunE: rewriteCmdRun,
+}
+
+func init() {
+	RootCmd.AddCommand(rewriteCmd)
+
+	rewriteRulesFlag = rewriteCmd.PersistentFlags().String("rules", "", "File with rewrite rules, one per line")
+	rewriteStepsFlag = rewriteCmd.PersistentFlags().Int("steps", 1000, "Maximum number of rewriting steps")
+	rewriteLimitFlags = addTreeLimitFlags(rewriteCmd)
+}
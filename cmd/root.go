@@ -24,9 +24,15 @@
 package cmd
 
 import (
+	"bufio"
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"time"
 
+	"github.com/pdobrowo/mm/math"
 	"github.com/spf13/cobra"
 )
 
@@ -38,9 +44,225 @@ command line. It supports formatting, semi-automatic simplification
 and statistical analysis.`,
 }
 
+func init() {
+	implicitMulFlag = RootCmd.PersistentFlags().String("implicit-mul", "permissive", "Implicit multiplication: off, strict or permissive")
+	explainFlag = RootCmd.PersistentFlags().Bool("explain", false, "Report implicit multiplications")
+}
+
 func Execute() {
 	if err := RootCmd.Execute(); err != nil {
 		fmt.Println(err)
 		os.Exit(-1)
 	}
 }
+
+var implicitMulFlag *string
+var explainFlag *bool
+
+// newParser returns a parser with the implicit multiplication policy of the
+// flags, reporting implicit multiplications to the standard error with
+// --explain.
+func newParser() (*math.Parser, error) {
+	policy, err := math.ParseMulPolicy(*implicitMulFlag)
+
+	if err != nil {
+		return nil, err
+	}
+
+	parser := &math.Parser{Mul: policy}
+
+	if *explainFlag {
+		parser.Explain = func(mul math.ImplicitMul) {
+			fmt.Fprintln(os.Stderr, mul)
+		}
+	}
+
+	return parser, nil
+}
+
+// parseInput parses an expression with the parser of the flags.
+func parseInput(reader io.Reader) (*math.Expr, error) {
+	parser, err := newParser()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return parser.Parse(reader)
+}
+
+// openInput returns a reader for the file named by the only argument, or for
+// the standard input if there are no arguments.
+func openInput(args []string) (io.Reader, error) {
+	switch len(args) {
+	case 0:
+		return bufio.NewReader(os.Stdin), nil
+	case 1:
+		reader, err := os.Open(args[0])
+
+		if err != nil {
+			return nil, fmt.Errorf("failed to open file: %v", args[0])
+		}
+
+		return reader, nil
+	default:
+		return nil, fmt.Errorf("invalid number of arguments: %d", len(args))
+	}
+}
+
+// parseFile parses the expression in a file.
+func parseFile(path string) (*math.Expr, error) {
+	file, err := os.Open(path)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %v", path)
+	}
+
+	defer file.Close()
+
+	expr, err := parseInput(file)
+
+	if err != nil {
+		return nil, fmt.Errorf("%v: %v", path, err)
+	}
+
+	return expr, nil
+}
+
+// readPolys expands the expressions given one per line in a file.
+func readPolys(ctx context.Context, path string, ring math.Ring, limits math.Limits) ([]math.Poly, error) {
+	file, err := os.Open(path)
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %v", path)
+	}
+
+	defer file.Close()
+
+	return expandLines(ctx, file, ring, limits, path)
+}
+
+// expandLines expands the expressions read one per line within the limits;
+// errors are prefixed with the name of the input.
+func expandLines(ctx context.Context, reader io.Reader, ring math.Ring, limits math.Limits, name string) ([]math.Poly, error) {
+	parser, err := newParser()
+
+	if err != nil {
+		return nil, err
+	}
+
+	exprs, err := parser.ParseLines(reader)
+
+	if err != nil {
+		return nil, fmt.Errorf("%v: %v", name, err)
+	}
+
+	polys := make([]math.Poly, len(exprs))
+
+	for i, expr := range exprs {
+		if polys[i], err = math.ExpandRing(ctx, expr, ring, limits, 1); err != nil {
+			return nil, fmt.Errorf("%v: %w", name, err)
+		}
+	}
+
+	return polys, nil
+}
+
+// modRing returns the ring of integers modulo p, or Z if p is 0.
+func modRing(p uint64) (math.Ring, error) {
+	if p == 0 {
+		return math.Integers, nil
+	}
+
+	return math.NewModular(p)
+}
+
+// orderFlags are the flags choosing the monomial order of a command.
+type orderFlags struct {
+	order *string
+	vars  *[]string
+}
+
+// addOrderFlags adds the order flags with the default order; an empty one
+// leaves terms in the order of the input unless the flag is given.
+func addOrderFlags(cmd *cobra.Command, order string) orderFlags {
+	usage := "Monomial order: lex, grlex (deglex) or grevlex (degrevlex)"
+
+	if order == "" {
+		usage += "; terms keep the input order if not given"
+	}
+
+	return orderFlags{
+		order: cmd.PersistentFlags().String("order", order, usage),
+		vars:  cmd.PersistentFlags().StringSlice("vars", nil, "Variables from the largest; others rank below them alphabetically"),
+	}
+}
+
+func (flags orderFlags) parse() (math.Order, error) {
+	order, err := math.ParseOrder(*flags.order)
+
+	if err != nil || len(*flags.vars) == 0 {
+		return order, err
+	}
+
+	return math.WithVars(order, *flags.vars)
+}
+
+// limitFlags are the flags bounding the resources of a transformation. The
+// limits a command does not offer are nil.
+type limitFlags struct {
+	timeout  *time.Duration
+	maxTerms *int
+	maxDepth *int
+}
+
+func addLimitFlags(cmd *cobra.Command) limitFlags {
+	return limitFlags{
+		timeout:  addTimeoutFlag(cmd),
+		maxTerms: cmd.PersistentFlags().Int("max-terms", 0, "Give up when a polynomial exceeds the given number of terms (0 for no limit)"),
+	}
+}
+
+// addTreeLimitFlags adds the limits of transformations which work on trees
+// rather than polynomials.
+func addTreeLimitFlags(cmd *cobra.Command) limitFlags {
+	return limitFlags{
+		timeout:  addTimeoutFlag(cmd),
+		maxDepth: cmd.PersistentFlags().Int("max-depth", 0, "Give up when the expression tree grows deeper than the given depth (0 for no limit)"),
+	}
+}
+
+func addTimeoutFlag(cmd *cobra.Command) *time.Duration {
+	return cmd.PersistentFlags().Duration("timeout", 0, "Give up after the given time, e.g. 30s (0 for no limit)")
+}
+
+func (flags limitFlags) context() (context.Context, context.CancelFunc) {
+	if *flags.timeout > 0 {
+		return context.WithTimeout(context.Background(), *flags.timeout)
+	}
+
+	return context.WithCancel(context.Background())
+}
+
+func (flags limitFlags) limits() math.Limits {
+	var limits math.Limits
+
+	if flags.maxTerms != nil {
+		limits.MaxTerms = *flags.maxTerms
+	}
+
+	if flags.maxDepth != nil {
+		limits.MaxDepth = *flags.maxDepth
+	}
+
+	return limits
+}
+
+// explain rewords errors caused by the limits for the command line.
+func (flags limitFlags) explain(err error) error {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Errorf("timed out after %v", *flags.timeout)
+	}
+
+	return err
+}
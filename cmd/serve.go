@@ -0,0 +1,236 @@
+// Copyright (c) 2017 Przemysław Dobrowolski
+//
+// This file is part of the math-mod, a package for symbolic manipulation
+// of large algebraic expressions.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	gomath "math"
+	"net/http"
+	"time"
+
+	"github.com/pdobrowo/mm/math"
+	"github.com/spf13/cobra"
+)
+
+var serveAddrFlag *string
+var serveMaxBytesFlag *int64
+var serveTimeoutFlag *time.Duration
+var serveMaxTermsFlag *int
+
+type serveRequest struct {
+	Expr string             `json:"expr"`
+	Vars map[string]float64 `json:"vars,omitempty"`
+	// Distinct asks /stats to count distinct subtrees as well.
+	Distinct bool `json:"distinct,omitempty"`
+}
+
+// serveError is the body of a failed request. Position is the byte offset
+// into the expression for syntax errors.
+type serveError struct {
+	Message  string `json:"message"`
+	Position *int   `json:"position,omitempty"`
+}
+
+type serveResponse struct {
+	Result interface{} `json:"result,omitempty"`
+	Error  *serveError `json:"error,omitempty"`
+}
+
+const serveTimeoutBody = `{"error":{"message":"request timed out"}}`
+
+var serveEndpoints = map[string]func(context.Context, *math.Expr, serveRequest, math.Limits) (interface{}, error){
+	"/format": func(ctx context.Context, expr *math.Expr, req serveRequest, limits math.Limits) (interface{}, error) {
+		return expr.String(), nil
+	},
+	"/postfix": func(ctx context.Context, expr *math.Expr, req serveRequest, limits math.Limits) (interface{}, error) {
+		return expr.Postfix().Join(), nil
+	},
+	"/expand": func(ctx context.Context, expr *math.Expr, req serveRequest, limits math.Limits) (interface{}, error) {
+		poly, err := math.ExpandContext(ctx, expr, limits)
+		if err != nil {
+			return nil, err
+		}
+		return poly.String(), nil
+	},
+	"/eval": func(ctx context.Context, expr *math.Expr, req serveRequest, limits math.Limits) (interface{}, error) {
+		value, err := math.Eval(expr, req.Vars)
+		if err != nil {
+			return nil, err
+		}
+		// JSON has no infinities nor NaN
+		if gomath.IsInf(value, 0) || gomath.IsNaN(value) {
+			return nil, fmt.Errorf("result is not finite: %v", value)
+		}
+		return value, nil
+	},
+	"/stats": func(ctx context.Context, expr *math.Expr, req serveRequest, limits math.Limits) (interface{}, error) {
+		if req.Distinct {
+			table := math.NewTable()
+			return table.Stats(table.Intern(expr)), nil
+		}
+		return expr.Stats(), nil
+	},
+}
+
+func writeServeResponse(w http.ResponseWriter, status int, response serveResponse) {
+	// marshal first, the status cannot be changed once it is written
+	data, err := json.Marshal(response)
+
+	if err != nil {
+		status = http.StatusInternalServerError
+		data, _ = json.Marshal(serveResponse{Error: &serveError{Message: fmt.Sprintf("cannot encode response: %v", err)}})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(append(data, '\n'))
+}
+
+func writeServeError(w http.ResponseWriter, status int, err error) {
+	body := &serveError{Message: err.Error()}
+
+	var parseErr *math.ParseError
+
+	if errors.As(err, &parseErr) {
+		body.Message = parseErr.Msg
+		body.Position = &parseErr.Pos
+	}
+
+	writeServeResponse(w, status, serveResponse{Error: body})
+}
+
+// serveFailure returns the status and the error reported for a failed
+// transformation, telling apart requests which ran out of time, exceeded
+// the limits of the server or failed on the expression itself.
+func serveFailure(err error) (int, error) {
+	var limitErr *math.LimitError
+
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return http.StatusServiceUnavailable, fmt.Errorf("request timed out")
+	case errors.Is(err, context.Canceled):
+		return http.StatusServiceUnavailable, fmt.Errorf("request canceled")
+	case errors.As(err, &limitErr):
+		return http.StatusUnprocessableEntity, fmt.Errorf("result exceeds the server limits: %v", err)
+	}
+
+	return http.StatusUnprocessableEntity, err
+}
+
+func serveEndpoint(endpoint func(context.Context, *math.Expr, serveRequest, math.Limits) (interface{}, error), parser *math.Parser, maxBytes int64, limits math.Limits) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			writeServeError(w, http.StatusMethodNotAllowed, fmt.Errorf("method not allowed: %v", r.Method))
+			return
+		}
+
+		var req serveRequest
+
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBytes)).Decode(&req); err != nil {
+			var tooLarge *http.MaxBytesError
+
+			if errors.As(err, &tooLarge) {
+				writeServeError(w, http.StatusRequestEntityTooLarge, fmt.Errorf("request body exceeds %d bytes", tooLarge.Limit))
+			} else {
+				writeServeError(w, http.StatusBadRequest, fmt.Errorf("invalid request body: %v", err))
+			}
+			return
+		}
+
+		expr, err := parser.ParseString(req.Expr)
+
+		if err != nil {
+			writeServeError(w, http.StatusBadRequest, err)
+			return
+		}
+
+		result, err := endpoint(r.Context(), expr, req, limits)
+
+		if err != nil {
+			status, err := serveFailure(err)
+			writeServeError(w, status, err)
+			return
+		}
+
+		writeServeResponse(w, http.StatusOK, serveResponse{Result: result})
+	}
+}
+
+// newServeHandler routes every endpoint, parsing expressions with the
+// parser and limiting the size of request bodies, the time spent on a single
+// request and the size of the results.
+func newServeHandler(parser *math.Parser, maxBytes int64, timeout time.Duration, limits math.Limits) http.Handler {
+	mux := http.NewServeMux()
+
+	for path, endpoint := range serveEndpoints {
+		mux.Handle(path, serveEndpoint(endpoint, parser, maxBytes, limits))
+	}
+
+	return http.TimeoutHandler(mux, timeout, serveTimeoutBody)
+}
+
+func serveCmdRun(cmd *cobra.Command, args []string) error {
+	if len(args) != 0 {
+		return fmt.Errorf("invalid number of arguments: %d", len(args))
+	}
+
+	parser, err := newParser()
+
+	if err != nil {
+		return err
+	}
+
+	server := &http.Server{
+		Addr:              *serveAddrFlag,
+		Handler:           newServeHandler(parser, *serveMaxBytesFlag, *serveTimeoutFlag, math.Limits{MaxTerms: *serveMaxTermsFlag}),
+		ReadHeaderTimeout: *serveTimeoutFlag,
+	}
+
+	return server.ListenAndServe()
+}
+
+// serveCmd represents the serve command
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve transformations over HTTP",
+	Long: `Serve format, postfix, expand, eval and stats as JSON endpoints.
+Each endpoint takes a POST request with a body like
+{"expr": "x^2 + y", "vars": {"x": 1, "y": 2}} and answers with
+{"result": ...} or {"error": {"message": ..., "position": ...}}.
+Stats count distinct subtrees if the request sets
+"distinct": true.`,
+	RunE: serveCmdRun,
+}
+
+func init() {
+	RootCmd.AddCommand(serveCmd)
+
+	serveAddrFlag = serveCmd.PersistentFlags().String("addr", ":8080", "Address to listen on")
+	serveMaxBytesFlag = serveCmd.PersistentFlags().Int64("max-bytes", 1<<20, "Maximum size of a request body in bytes")
+	serveTimeoutFlag = serveCmd.PersistentFlags().Duration("timeout", 10*time.Second, "Maximum time to handle a request")
+	serveMaxTermsFlag = serveCmd.PersistentFlags().Int("max-terms", 100000, "Maximum number of terms of a polynomial (0 for no limit)")
+}
@@ -0,0 +1,163 @@
+// Copyright (c) 2017 Przemysław Dobrowolski
+//
+// This file is part of the math-mod, a package for symbolic manipulation
+// of large algebraic expressions.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package cmd
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	"github.com/pdobrowo/mm/math"
+)
+
+var _ = Describe("Serve Object", func() {
+	var server *httptest.Server
+
+	BeforeEach(func() {
+		server = httptest.NewServer(newServeHandler(&math.Parser{}, 64, time.Second, math.Limits{MaxTerms: 10}))
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	post := func(path, body string) (int, string) {
+		resp, err := http.Post(server.URL+path, "application/json", strings.NewReader(body))
+		Expect(err).ShouldNot(HaveOccurred())
+		defer resp.Body.Close()
+
+		data, err := io.ReadAll(resp.Body)
+		Expect(err).ShouldNot(HaveOccurred())
+		return resp.StatusCode, strings.TrimSpace(string(data))
+	}
+
+	Context("when an expression is expanded", func() {
+		It("should return the result", func() {
+			status, body := post("/expand", `{"expr": "(x + 1)^2"}`)
+			Expect(status).To(Equal(http.StatusOK))
+			Expect(body).To(Equal(`{"result":"x ^ 2 + 2 * x + 1"}`))
+		})
+	})
+
+	Context("when an expression is evaluated", func() {
+		It("should use the given variables", func() {
+			status, body := post("/eval", `{"expr": "x y", "vars": {"x": 2, "y": 3.5}}`)
+			Expect(status).To(Equal(http.StatusOK))
+			Expect(body).To(Equal(`{"result":7}`))
+		})
+	})
+
+	Context("when distinct subtrees are asked for", func() {
+		It("should count them", func() {
+			status, body := post("/stats", `{"expr": "(x+1)(x+1)", "distinct": true}`)
+			Expect(status).To(Equal(http.StatusOK))
+			Expect(body).To(ContainSubstring(`"nodes":7,"distinct":4,`))
+
+			_, body = post("/stats", `{"expr": "(x+1)(x+1)"}`)
+			Expect(body).NotTo(ContainSubstring(`"distinct"`))
+		})
+	})
+
+	Context("when multiplication is implicit", func() {
+		It("should follow the policy of the parser", func() {
+			server.Close()
+			server = httptest.NewServer(newServeHandler(&math.Parser{Mul: math.MulStrict}, 64, time.Second, math.Limits{}))
+
+			status, body := post("/format", `{"expr": "2x"}`)
+			Expect(status).To(Equal(http.StatusOK))
+			Expect(body).To(Equal(`{"result":"2 * x"}`))
+
+			status, _ = post("/format", `{"expr": "x y"}`)
+			Expect(status).To(Equal(http.StatusBadRequest))
+		})
+	})
+
+	Context("when the expression has a syntax error", func() {
+		It("should report its position", func() {
+			status, body := post("/format", `{"expr": "x + * y"}`)
+			Expect(status).To(Equal(http.StatusBadRequest))
+			Expect(body).To(Equal(`{"error":{"message":"missing operand for operator: *","position":4}}`))
+		})
+	})
+
+	Context("when the transformation fails", func() {
+		It("should report the error", func() {
+			status, body := post("/eval", `{"expr": "x"}`)
+			Expect(status).To(Equal(http.StatusUnprocessableEntity))
+			Expect(body).To(Equal(`{"error":{"message":"unbound variable: x"}}`))
+		})
+	})
+
+	Context("when the value is not finite", func() {
+		It("should report the error", func() {
+			for _, expr := range []string{"log(0)", "sqrt(0 - 1)", "1 / 0"} {
+				status, body := post("/eval", `{"expr": "`+expr+`"}`)
+				Expect(status).To(Equal(http.StatusUnprocessableEntity))
+				Expect(body).To(HavePrefix(`{"error":{"message":"result is not finite: `))
+			}
+		})
+	})
+
+	Context("when the result is too large", func() {
+		It("should be rejected", func() {
+			status, body := post("/expand", `{"expr": "(x + y)^10"}`)
+			Expect(status).To(Equal(http.StatusUnprocessableEntity))
+			Expect(body).To(Equal(`{"error":{"message":"result exceeds the server limits: terms limit exceeded: 10"}}`))
+		})
+	})
+
+	Context("when the transformation runs out of time", func() {
+		It("should be reported as unavailable", func() {
+			status, err := serveFailure(context.DeadlineExceeded)
+			Expect(status).To(Equal(http.StatusServiceUnavailable))
+			Expect(err).To(MatchError("request timed out"))
+
+			status, err = serveFailure(context.Canceled)
+			Expect(status).To(Equal(http.StatusServiceUnavailable))
+			Expect(err).To(MatchError("request canceled"))
+		})
+	})
+
+	Context("when the request is too large", func() {
+		It("should be rejected", func() {
+			status, _ := post("/stats", `{"expr": "`+strings.Repeat("x + ", 20)+`x"}`)
+			Expect(status).To(Equal(http.StatusRequestEntityTooLarge))
+		})
+	})
+
+	Context("when the method is not POST", func() {
+		It("should be rejected", func() {
+			resp, err := http.Get(server.URL + "/postfix")
+			Expect(err).ShouldNot(HaveOccurred())
+			resp.Body.Close()
+			Expect(resp.StatusCode).To(Equal(http.StatusMethodNotAllowed))
+		})
+	})
+})
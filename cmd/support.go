@@ -0,0 +1,165 @@
+// Copyright (c) 2017 Przemysław Dobrowolski
+//
+// This file is part of the math-mod, a package for symbolic manipulation
+// of large algebraic expressions.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package cmd
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/pdobrowo/mm/math"
+	"github.com/spf13/cobra"
+)
+
+var supportToFlag *string
+var supportPolytopeFlag *bool
+var supportFacetsFlag *bool
+var supportLimitFlags limitFlags
+
+func supportCmdRun(cmd *cobra.Command, args []string) error {
+	if *supportToFlag != "csv" && *supportToFlag != "json" {
+		return fmt.Errorf("unknown output format: %v", *supportToFlag)
+	}
+
+	if *supportFacetsFlag && (!*supportPolytopeFlag || *supportToFlag != "csv") {
+		return fmt.Errorf("--facets requires --polytope and CSV output")
+	}
+
+	reader, err := openInput(args)
+
+	if err != nil {
+		return err
+	}
+
+	expr, err := parseInput(reader)
+
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := supportLimitFlags.context()
+	defer cancel()
+
+	poly, err := math.ExpandContext(ctx, expr, supportLimitFlags.limits())
+
+	if err != nil {
+		return supportLimitFlags.explain(err)
+	}
+
+	vars, points := poly.Support()
+
+	if !*supportPolytopeFlag {
+		if *supportToFlag == "json" {
+			return printJSON(struct {
+				Vars   []string `json:"vars"`
+				Points [][]int  `json:"points"`
+			}{vars, points})
+		}
+
+		return writeCSV(vars, points)
+	}
+
+	if poly.IsZero() {
+		return fmt.Errorf("zero polynomial has no Newton polytope")
+	}
+
+	polytope, err := math.NewtonPolytopeContext(ctx, poly, supportLimitFlags.limits())
+
+	if err != nil {
+		return supportLimitFlags.explain(err)
+	}
+
+	switch {
+	case *supportToFlag == "json":
+		return printJSON(polytope)
+	case *supportFacetsFlag:
+		if polytope.Facets == nil && polytope.Dimension > 0 {
+			return fmt.Errorf("facets are given for up to 3 variables, got %d", len(polytope.Vars))
+		}
+		return writeCSV(nil, polytope.Facets)
+	default:
+		return writeCSV(polytope.Vars, polytope.Vertices)
+	}
+}
+
+func printJSON(value interface{}) error {
+	data, err := json.Marshal(value)
+
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(string(data))
+
+	return nil
+}
+
+// writeCSV prints rows of integers, preceded by the header if it is given.
+func writeCSV(header []string, rows [][]int) error {
+	writer := csv.NewWriter(os.Stdout)
+
+	if header != nil {
+		if err := writer.Write(header); err != nil {
+			return err
+		}
+	}
+
+	for _, row := range rows {
+		record := make([]string, len(row))
+		for i, value := range row {
+			record[i] = strconv.Itoa(value)
+		}
+
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// supportCmd represents the support command
+var supportCmd = &cobra.Command{
+	Use:   "support",
+	Short: "Export the support or the Newton polytope of an expression",
+	Long: `Expand an expression and print the exponent vectors of
+its terms, one per row with a header of variable names.
+With --polytope print the vertices of its Newton polytope
+instead and, with --facets, the facets as rows of vertex
+indices, which are given for up to 3 variables. JSON
+output holds the variables, vertices and facets together.`,
+	RunE: supportCmdRun,
+}
+
+func init() {
+	RootCmd.AddCommand(supportCmd)
+
+	supportToFlag = supportCmd.PersistentFlags().String("to", "csv", "Output format: csv or json")
+	supportPolytopeFlag = supportCmd.PersistentFlags().Bool("polytope", false, "Print the Newton polytope")
+	supportFacetsFlag = supportCmd.PersistentFlags().Bool("facets", false, "Print facets of the Newton polytope in CSV")
+	supportLimitFlags = addLimitFlags(supportCmd)
+}
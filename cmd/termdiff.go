@@ -0,0 +1,112 @@
+// Copyright (c) 2017 Przemysław Dobrowolski
+//
+// This file is part of the math-mod, a package for symbolic manipulation
+// of large algebraic expressions.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package cmd
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/pdobrowo/mm/math"
+	"github.com/spf13/cobra"
+)
+
+var termdiffSummaryFlag *bool
+var termdiffModFlag *uint64
+var termdiffOrderFlags orderFlags
+var termdiffLimitFlags limitFlags
+
+func termdiffCmdRun(cmd *cobra.Command, args []string) error {
+	if len(args) != 2 {
+		return fmt.Errorf("invalid number of arguments: %d", len(args))
+	}
+
+	order, err := termdiffOrderFlags.parse()
+
+	if err != nil {
+		return err
+	}
+
+	ring, err := modRing(*termdiffModFlag)
+
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := termdiffLimitFlags.context()
+	defer cancel()
+
+	polys := make([]math.Poly, len(args))
+
+	for i, arg := range args {
+		expr, err := parseFile(arg)
+
+		if err != nil {
+			return err
+		}
+
+		if polys[i], err = math.ExpandRing(ctx, expr, ring, termdiffLimitFlags.limits(), 1); err != nil {
+			return termdiffLimitFlags.explain(err)
+		}
+	}
+
+	diff := math.DiffTerms(polys[0], polys[1], order)
+
+	if !*termdiffSummaryFlag {
+		for _, term := range diff.OnlyA {
+			fmt.Println("<", term.Expr())
+		}
+		for _, term := range diff.OnlyB {
+			fmt.Println(">", term.Expr())
+		}
+		for _, change := range diff.Changed {
+			mono := math.Term{Coeff: big.NewRat(1, 1), Mono: change.Mono}.Expr()
+			fmt.Printf("! %v: %v -> %v\n", mono, change.A.RatString(), change.B.RatString())
+		}
+	}
+
+	fmt.Printf("only in a: %d, only in b: %d, changed: %d, common: %d\n", len(diff.OnlyA), len(diff.OnlyB), len(diff.Changed), diff.Common)
+
+	return nil
+}
+
+// termdiffCmd represents the termdiff command
+var termdiffCmd = &cobra.Command{
+	Use:   "termdiff a b",
+	Short: "Compare two expressions term by term",
+	Long: `Expand the expressions in two files and compare them
+term by term. Terms only in the first expansion are
+printed after <, terms only in the second after >, and
+monomials with different coefficients after !, followed
+by a summary of the counts.`,
+	RunE: termdiffCmdRun,
+}
+
+func init() {
+	RootCmd.AddCommand(termdiffCmd)
+
+	termdiffSummaryFlag = termdiffCmd.PersistentFlags().Bool("summary", false, "Print the summary only")
+	termdiffModFlag = termdiffCmd.PersistentFlags().Uint64("mod", 0, "Compare with coefficients modulo the given prime")
+	termdiffOrderFlags = addOrderFlags(termdiffCmd, "grlex")
+	termdiffLimitFlags = addLimitFlags(termdiffCmd)
+}
@@ -0,0 +1,85 @@
+// Copyright (c) 2017 Przemysław Dobrowolski
+//
+// This file is part of the math-mod, a package for symbolic manipulation
+// of large algebraic expressions.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/pdobrowo/mm/math"
+	"github.com/spf13/cobra"
+)
+
+var togetherLimitFlags limitFlags
+var togetherOrderFlags orderFlags
+
+func togetherCmdRun(cmd *cobra.Command, args []string) error {
+	order, err := togetherOrderFlags.parse()
+
+	if err != nil {
+		return err
+	}
+
+	reader, err := openInput(args)
+
+	if err != nil {
+		return err
+	}
+
+	expr, err := parseInput(reader)
+
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := togetherLimitFlags.context()
+	defer cancel()
+
+	f, err := math.TogetherContext(ctx, expr, togetherLimitFlags.limits())
+
+	if err != nil {
+		return togetherLimitFlags.explain(err)
+	}
+
+	fmt.Println(f.ExprIn(order).Infix())
+
+	return nil
+}
+
+// togetherCmd represents the together command
+var togetherCmd = &cobra.Command{
+	Use:   "together",
+	Short: "Bring a sum of fractions to a common denominator",
+	Long: `Bring an algebraic expression with divisions to a single
+quotient of expanded polynomials, cancelling their common
+factors. Coefficients are coprime integers and the leading
+coefficient of the denominator is positive.`,
+	RunE: togetherCmdRun,
+}
+
+func init() {
+	RootCmd.AddCommand(togetherCmd)
+
+	togetherLimitFlags = addLimitFlags(togetherCmd)
+	togetherOrderFlags = addOrderFlags(togetherCmd, "grlex")
+}
@@ -0,0 +1,433 @@
+// Copyright (c) 2017 Przemysław Dobrowolski
+//
+// This file is part of the math-mod, a package for symbolic manipulation
+// of large algebraic expressions.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package math
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+)
+
+// maxDivisorSearch bounds the integers whose divisors are tried as roots and
+// factor values when the denominator is factored.
+const maxDivisorSearch = 1 << 40
+
+// maxQuadraticSearch bounds the number of candidate quadratic factors tried.
+const maxQuadraticSearch = 1 << 20
+
+// PartialFraction is the term Num/Factor^Exp of a decomposition. The factor
+// has coprime integer coefficients and a positive leading coefficient, and
+// the numerator has a lower degree than the factor.
+type PartialFraction struct {
+	Num    Poly
+	Factor Poly
+	Exp    int
+}
+
+// PartialFractions is a rational function written as a polynomial plus a
+// sum of partial fractions, ordered by factor degree and then by exponent.
+type PartialFractions struct {
+	Poly      Poly
+	Fractions []PartialFraction
+}
+
+// Apart decomposes a rational function of one variable into partial
+// fractions over Q. The denominator is split into linear and quadratic
+// factors where they have rational coefficients; remaining factors, which
+// are irreducible if of degree 3 or less, are kept whole.
+func Apart(expr *Expr, name string) (PartialFractions, error) {
+	return apart(expr, name, nil)
+}
+
+// ApartContext works like Apart, but gives up when the context is done or the
+// limits are exceeded.
+func ApartContext(ctx context.Context, expr *Expr, name string, limits Limits) (PartialFractions, error) {
+	return apart(expr, name, newGuard(ctx, limits))
+}
+
+func apart(expr *Expr, name string, g *guard) (PartialFractions, error) {
+	f, err := together(expr, g, 1)
+
+	if err != nil {
+		return PartialFractions{}, err
+	}
+
+	num, numOk := toDense(f.Num, name)
+	den, denOk := toDense(f.Den, name)
+
+	if !numOk || !denOk {
+		return PartialFractions{}, fmt.Errorf("not a rational function of %v alone: %v", name, expr)
+	}
+
+	quo, rem := num.divMod(den)
+	result := PartialFractions{Poly: quo.poly(name)}
+
+	if len(rem) == 0 {
+		return result, nil
+	}
+
+	rem = rem.scale(new(big.Rat).Inv(den.lead()))
+
+	var factors []denseFactor
+
+	for _, factor := range den.squareFree() {
+		split, err := factor.base.split(g)
+
+		if err != nil {
+			return PartialFractions{}, err
+		}
+
+		for _, irreducible := range split {
+			factors = append(factors, denseFactor{base: irreducible, exp: factor.exp})
+		}
+	}
+
+	sort.Slice(factors, func(i, j int) bool {
+		if factors[i].base.degree() != factors[j].base.degree() {
+			return factors[i].base.degree() < factors[j].base.degree()
+		}
+		return factors[i].base.poly(name).String() < factors[j].base.poly(name).String()
+	})
+
+	powers := make([]dense, len(factors))
+
+	for i, factor := range factors {
+		powers[i] = dense{big.NewRat(1, 1)}
+		for k := 0; k < factor.exp; k++ {
+			powers[i] = powers[i].mul(factor.base)
+		}
+	}
+
+	for i, factor := range factors {
+		// the numerator over this power is rem/cofactor modulo the power
+		cofactor := dense{big.NewRat(1, 1)}
+		for j, power := range powers {
+			if j != i {
+				cofactor = cofactor.mul(power)
+			}
+		}
+
+		_, part := rem.mul(cofactor.invMod(powers[i])).divMod(powers[i])
+
+		if err := g.step(); err != nil {
+			return PartialFractions{}, err
+		}
+
+		// expand part in powers of the factor: the lowest digit goes over
+		// the highest power
+		scale := new(big.Rat).SetInt(factor.base.commonDenominator())
+		base := factor.base.scale(scale).poly(name)
+		var fractions []PartialFraction
+
+		for exp := factor.exp; len(part) > 0; exp-- {
+			var digit dense
+			part, digit = part.divMod(factor.base)
+
+			if len(digit) > 0 {
+				for k := 0; k < exp; k++ {
+					digit = digit.scale(scale)
+				}
+				fractions = append(fractions, PartialFraction{Num: digit.poly(name), Factor: base, Exp: exp})
+			}
+		}
+
+		for k := len(fractions) - 1; k >= 0; k-- {
+			result.Fractions = append(result.Fractions, fractions[k])
+		}
+	}
+
+	return result, nil
+}
+
+// Expr converts the decomposition to an expression tree. Fractional
+// coefficients of a numerator are moved to its denominator.
+func (pf PartialFractions) Expr() *Expr {
+	var result *Expr
+
+	if !pf.Poly.IsZero() || len(pf.Fractions) == 0 {
+		result = pf.Poly.Expr()
+	}
+
+	for _, fraction := range pf.Fractions {
+		num := fraction.Num
+		negative := result != nil && num.Terms()[0].Coeff.Sign() < 0
+
+		if negative {
+			num = num.Neg()
+		}
+
+		scale := num.commonDenominator()
+		den := fraction.Factor.Expr()
+
+		if fraction.Exp != 1 {
+			den = NewNode(NewPow(), den, NewLeaf(NewInt(int64(fraction.Exp))))
+		}
+		if scale.Cmp(big.NewInt(1)) != 0 {
+			den = NewNode(NewMul(), NewLeaf(NewBigInt(scale)), den)
+		}
+
+		term := NewNode(NewDiv(), num.scale(new(big.Rat).SetInt(scale)).Expr(), den)
+
+		switch {
+		case result == nil:
+			result = term
+		case negative:
+			result = NewNode(NewMinus(), result, term)
+		default:
+			result = NewNode(NewPlus(), result, term)
+		}
+	}
+
+	return result
+}
+
+func (pf PartialFractions) String() string {
+	return pf.Expr().String()
+}
+
+// commonDenominator returns the least common multiple of the denominators of
+// the coefficients.
+func (poly Poly) commonDenominator() *big.Int {
+	lcm := big.NewInt(1)
+
+	for _, term := range poly.terms {
+		lcm = lcmInt(lcm, term.Coeff.Denom())
+	}
+	return lcm
+}
+
+func (d dense) commonDenominator() *big.Int {
+	lcm := big.NewInt(1)
+
+	for _, coeff := range d {
+		lcm = lcmInt(lcm, coeff.Denom())
+	}
+	return lcm
+}
+
+func lcmInt(a, b *big.Int) *big.Int {
+	gcd := new(big.Int).GCD(nil, nil, a, b)
+	return new(big.Int).Mul(a, new(big.Int).Div(b, gcd))
+}
+
+// denseFactor is a factor raised to a positive exponent.
+type denseFactor struct {
+	base dense
+	exp  int
+}
+
+// squareFree returns the square-free decomposition of a polynomial of
+// positive degree as monic pairwise coprime factors with distinct exponents,
+// computed with Yun's algorithm.
+func (d dense) squareFree() (factors []denseFactor) {
+	d = d.monic()
+	diff := d.diff()
+	gcd := d.gcd(diff)
+	w, _ := d.divMod(gcd)
+	y, _ := diff.divMod(gcd)
+	z := y.sub(w.diff())
+
+	for exp := 1; w.degree() > 0; exp++ {
+		base := w.gcd(z)
+		if base.degree() > 0 {
+			factors = append(factors, denseFactor{base: base, exp: exp})
+		}
+		w, _ = w.divMod(base)
+		y, _ = z.divMod(base)
+		z = y.sub(w.diff())
+	}
+	return
+}
+
+// split factors a monic square-free polynomial into linear factors of its
+// rational roots, quadratic factors with rational coefficients and the
+// remaining cofactor.
+func (d dense) split(g *guard) (factors []dense, err error) {
+	for d.degree() > 1 {
+		root, ok, err := d.rationalRoot(g)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			break
+		}
+		linear := dense{new(big.Rat).Neg(root), big.NewRat(1, 1)}
+		factors = append(factors, linear)
+		d, _ = d.divMod(linear)
+	}
+
+	for d.degree() >= 4 {
+		quadratic, ok, err := d.quadraticFactor(g)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			break
+		}
+		factors = append(factors, quadratic)
+		d, _ = d.divMod(quadratic)
+	}
+
+	if d.degree() > 0 {
+		factors = append(factors, d.monic())
+	}
+	return factors, nil
+}
+
+// integers returns the coefficients scaled to integers.
+func (d dense) integers() []*big.Int {
+	scale := new(big.Rat).SetInt(d.commonDenominator())
+	result := make([]*big.Int, len(d))
+
+	for i, coeff := range d {
+		result[i] = new(big.Rat).Mul(coeff, scale).Num()
+	}
+	return result
+}
+
+// rationalRoot finds a root p/q with p dividing the constant and q the
+// leading coefficient of the integer form.
+func (d dense) rationalRoot(g *guard) (*big.Rat, bool, error) {
+	coeffs := d.integers()
+
+	if coeffs[0].Sign() == 0 {
+		return new(big.Rat), true, nil
+	}
+
+	nums, numOk, err := divisors(coeffs[0], g)
+
+	if err != nil {
+		return nil, false, err
+	}
+
+	dens, denOk, err := divisors(coeffs[len(coeffs)-1], g)
+
+	if err != nil || !numOk || !denOk {
+		return nil, false, err
+	}
+
+	for _, num := range nums {
+		for _, den := range dens {
+			if err := g.step(); err != nil {
+				return nil, false, err
+			}
+
+			root := new(big.Rat).SetFrac(num, den)
+			if d.eval(root).Sign() == 0 {
+				return root, true, nil
+			}
+			if d.eval(root.Neg(root)).Sign() == 0 {
+				return root, true, nil
+			}
+		}
+	}
+	return nil, false, nil
+}
+
+// quadraticFactor finds a factor a x^2 + b x + c with integer coefficients
+// by Kronecker's method: its values at 0, 1 and -1 divide the values of the
+// polynomial there, which are non-zero if it has no rational roots.
+func (d dense) quadraticFactor(g *guard) (dense, bool, error) {
+	coeffs := d.integers()
+	integral := make(dense, len(coeffs))
+
+	for i, coeff := range coeffs {
+		integral[i] = new(big.Rat).SetInt(coeff)
+	}
+
+	var values [3][]*big.Int
+
+	for i, point := range []int64{0, 1, -1} {
+		divs, ok, err := divisors(integral.eval(big.NewRat(point, 1)).Num(), g)
+		if err != nil || !ok {
+			return nil, false, err
+		}
+		for _, div := range divs {
+			values[i] = append(values[i], div, new(big.Int).Neg(div))
+		}
+	}
+
+	if len(values[0])*len(values[1])*len(values[2]) > maxQuadraticSearch {
+		return nil, false, nil
+	}
+
+	two := big.NewInt(2)
+
+	for _, c := range values[0] {
+		for _, plus := range values[1] {
+			for _, minus := range values[2] {
+				if err := g.step(); err != nil {
+					return nil, false, err
+				}
+
+				// plus = a + b + c, minus = a - b + c
+				a := new(big.Int).Sub(new(big.Int).Add(plus, minus), new(big.Int).Mul(two, c))
+				b := new(big.Int).Sub(plus, minus)
+
+				if a.Sign() <= 0 || a.Bit(0) != 0 || b.Bit(0) != 0 {
+					continue
+				}
+
+				quadratic := dense{new(big.Rat).SetInt(c), new(big.Rat).SetFrac(b, two), new(big.Rat).SetFrac(a, two)}
+
+				if _, rem := d.divMod(quadratic); len(rem) == 0 {
+					return quadratic.monic(), true, nil
+				}
+			}
+		}
+	}
+	return nil, false, nil
+}
+
+// divisors returns the positive divisors of a non-zero integer, or false if
+// it is too large to search.
+func divisors(n *big.Int, g *guard) ([]*big.Int, bool, error) {
+	n = new(big.Int).Abs(n)
+
+	if n.Sign() == 0 || n.Cmp(big.NewInt(maxDivisorSearch)) > 0 {
+		return nil, false, nil
+	}
+
+	value := n.Int64()
+	var small, large []*big.Int
+
+	for i := int64(1); i*i <= value; i++ {
+		if err := g.step(); err != nil {
+			return nil, false, err
+		}
+
+		if value%i == 0 {
+			small = append(small, big.NewInt(i))
+			if i*i != value {
+				large = append(large, big.NewInt(value/i))
+			}
+		}
+	}
+
+	for i := len(large) - 1; i >= 0; i-- {
+		small = append(small, large[i])
+	}
+	return small, true, nil
+}
@@ -0,0 +1,98 @@
+// Copyright (c) 2017 Przemysław Dobrowolski
+//
+// This file is part of the math-mod, a package for symbolic manipulation
+// of large algebraic expressions.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package math
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"context"
+)
+
+func apartString(infix string) string {
+	pf, err := Apart(parseTree(infix), "x")
+	Expect(err).ShouldNot(HaveOccurred())
+	return pf.String()
+}
+
+var _ = Describe("Apart Object", func() {
+	Context("when the denominator has distinct linear factors", func() {
+		It("should give one fraction per factor", func() {
+			Expect(apartString("1 / (x^2 - 1)")).To(Equal("-1 / ( 2 * ( x + 1 ) ) + 1 / ( 2 * ( x - 1 ) )"))
+			Expect(apartString("x / (2 x^2 - 3 x + 1)")).To(Equal("1 / ( x - 1 ) - 1 / ( 2 * x - 1 )"))
+		})
+	})
+
+	Context("when the denominator has repeated and quadratic factors", func() {
+		It("should give fractions for all powers", func() {
+			Expect(apartString("(x^3 + 1) / (x^2 (x^2 + 1))")).To(Equal("1 / x ^ 2 + ( x - 1 ) / ( x ^ 2 + 1 )"))
+			Expect(apartString("1 / (x^2 + x + 1)^2 / (x - 1)")).
+				To(Equal("1 / ( 9 * ( x - 1 ) ) - ( x + 2 ) / ( 9 * ( x ^ 2 + x + 1 ) ) - ( x + 2 ) / ( 3 * ( x ^ 2 + x + 1 ) ^ 2 )"))
+			Expect(apartString("1 / ((x^2 + 2)(x^2 - 3))")).To(Equal("-1 / ( 5 * ( x ^ 2 + 2 ) ) + 1 / ( 5 * ( x ^ 2 - 3 ) )"))
+		})
+
+		It("should equal the original function", func() {
+			original := parseTree("(3 x^5 - x + 7) / ((x - 2)^3 (x^2 + 2 x + 5)^2 (x^4 + x^2 + 1))")
+			pf, err := Apart(original, "x")
+			Expect(err).ShouldNot(HaveOccurred())
+
+			for _, x := range []float64{-1.5, 0.25, 3} {
+				want, err := Eval(original, map[string]float64{"x": x})
+				Expect(err).ShouldNot(HaveOccurred())
+				got, err := Eval(pf.Expr(), map[string]float64{"x": x})
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(got).To(BeNumerically("~", want, 1e-9))
+			}
+
+			Expect(pf.Fractions).To(HaveLen(7))
+		})
+	})
+
+	Context("when the numerator has a higher degree", func() {
+		It("should keep the polynomial part first", func() {
+			Expect(apartString("(x^3 + 2) / (x + 1)")).To(Equal("x ^ 2 - x + 1 + 1 / ( x + 1 )"))
+			Expect(apartString("x^2 + 1")).To(Equal("x ^ 2 + 1"))
+		})
+	})
+
+	Context("when other variables occur", func() {
+		It("should fail", func() {
+			_, err := Apart(parseTree("1 / (x + y)"), "x")
+			Expect(err).Should(HaveOccurred())
+
+			_, err = ApartContext(context.Background(), parseTree("1 / x"), "x", Limits{MaxDepth: 1})
+			Expect(err).Should(BeAssignableToTypeOf(&LimitError{}))
+		})
+	})
+
+	Context("when the context is cancelled while factoring", func() {
+		It("should stop searching for roots", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+
+			_, err := ApartContext(ctx, parseTree("1 / (x^2 + 1099511627776)"), "x", Limits{})
+			Expect(err).To(Equal(context.Canceled))
+		})
+	})
+})
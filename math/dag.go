@@ -0,0 +1,263 @@
+// Copyright (c) 2017 Przemysław Dobrowolski
+//
+// This file is part of the math-mod, a package for symbolic manipulation
+// of large algebraic expressions.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package math
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// NodeID identifies an expression interned in a Table. Within one table
+// equal IDs mean structurally equal expressions and vice versa.
+type NodeID int
+
+type dagNode struct {
+	token Token
+	args  []NodeID
+}
+
+// Table stores expressions as a DAG in which every distinct subtree is kept
+// once. Redundant inputs, such as expanded powers of sums, take memory
+// proportional to the number of distinct subtrees rather than tree nodes.
+type Table struct {
+	nodes []dagNode
+	index map[string]NodeID
+}
+
+func NewTable() *Table {
+	return &Table{index: make(map[string]NodeID)}
+}
+
+// Len returns the number of distinct nodes in the table.
+func (table *Table) Len() int {
+	return len(table.nodes)
+}
+
+// Node interns the node with the given token and operands.
+func (table *Table) Node(token Token, args ...NodeID) NodeID {
+	key := nodeKey(token, args)
+
+	if id, ok := table.index[key]; ok {
+		return id
+	}
+
+	id := NodeID(len(table.nodes))
+	table.nodes = append(table.nodes, dagNode{token: token, args: append([]NodeID(nil), args...)})
+	table.index[key] = id
+	return id
+}
+
+// nodeKey encodes the token and the operand IDs; operands are interned
+// first, so equal keys mean identical subtrees.
+func nodeKey(token Token, args []NodeID) string {
+	value := token.String()
+	key := make([]byte, 0, 2*binary.MaxVarintLen64+len(value)+len(args)*binary.MaxVarintLen64)
+
+	key = binary.AppendUvarint(key, uint64(token.Kind))
+	key = binary.AppendUvarint(key, uint64(len(value)))
+	key = append(key, value...)
+
+	for _, arg := range args {
+		key = binary.AppendUvarint(key, uint64(arg))
+	}
+	return string(key)
+}
+
+// Intern adds the expression tree to the table and returns its ID.
+func (table *Table) Intern(expr *Expr) NodeID {
+	args := make([]NodeID, len(expr.Args))
+
+	for i, arg := range expr.Args {
+		args[i] = table.Intern(arg)
+	}
+	return table.Node(expr.Token, args...)
+}
+
+// InternPostfix adds the expression given in postfix to the table without
+// building its tree and returns its ID. It fails like ToTree.
+func (table *Table) InternPostfix(postfix Tokens) (NodeID, error) {
+	var stack []NodeID
+
+	for _, token := range postfix {
+		switch token.Kind {
+		case KindInt, KindVar:
+			stack = append(stack, table.Node(token))
+		case KindPlus, KindMinus, KindMul, KindDiv, KindPow:
+			if len(stack) < 2 {
+				return 0, fmt.Errorf("missing operand for operator: %v", token)
+			}
+			left, right := stack[len(stack)-2], stack[len(stack)-1]
+			stack = append(stack[:len(stack)-2], table.Node(token, left, right))
+		case KindFunc:
+			if len(stack) < 1 {
+				return 0, fmt.Errorf("missing argument for function: %v", token)
+			}
+			stack[len(stack)-1] = table.Node(token, stack[len(stack)-1])
+		default:
+			return 0, fmt.Errorf("unexpected token: %v", token)
+		}
+	}
+
+	switch len(stack) {
+	case 0:
+		return 0, fmt.Errorf("empty expression")
+	case 1:
+		return stack[0], nil
+	default:
+		return 0, fmt.Errorf("missing operator between operands")
+	}
+}
+
+func (table *Table) Token(id NodeID) Token {
+	return table.nodes[id].token
+}
+
+// Args returns the operands of the node, which must not be modified.
+func (table *Table) Args(id NodeID) []NodeID {
+	return table.nodes[id].args
+}
+
+// Expr converts the node back to a tree in which identical subtrees are
+// the same *Expr, so it must not be modified in place.
+func (table *Table) Expr(id NodeID) *Expr {
+	return table.expr(id, make(map[NodeID]*Expr))
+}
+
+func (table *Table) expr(id NodeID, built map[NodeID]*Expr) *Expr {
+	if expr, ok := built[id]; ok {
+		return expr
+	}
+
+	node := table.nodes[id]
+	args := make([]*Expr, len(node.args))
+
+	for i, arg := range node.args {
+		args[i] = table.expr(arg, built)
+	}
+
+	expr := NewNode(node.token, args...)
+	if len(args) == 0 {
+		expr = NewLeaf(node.token)
+	}
+
+	built[id] = expr
+	return expr
+}
+
+// TreeSize returns the number of nodes of the expression as a tree,
+// saturating at the largest int since shared subtrees may grow it
+// exponentially.
+func (table *Table) TreeSize(id NodeID) int {
+	sizes := make(map[NodeID]int)
+
+	var size func(id NodeID) int
+	size = func(id NodeID) int {
+		if s, ok := sizes[id]; ok {
+			return s
+		}
+
+		s := 1
+		for _, arg := range table.nodes[id].args {
+			s = addSaturating(s, size(arg))
+		}
+
+		sizes[id] = s
+		return s
+	}
+
+	return size(id)
+}
+
+// addSaturating adds non-negative counts, saturating at the largest int.
+func addSaturating(a, b int) int {
+	if sum := a + b; sum >= 0 {
+		return sum
+	}
+	return int(^uint(0) >> 1)
+}
+
+// Transformer applies a bottom-up transformation to interned expressions.
+// Results are memoised across calls, so each distinct node is transformed
+// once no matter how often it occurs.
+type Transformer struct {
+	table *Table
+	fn    func(table *Table, token Token, args []NodeID) NodeID
+	memo  map[NodeID]NodeID
+}
+
+// NewTransformer returns a transformer calling fn with each node's token
+// and already transformed operands; fn returns the transformed node.
+func (table *Table) NewTransformer(fn func(table *Table, token Token, args []NodeID) NodeID) *Transformer {
+	return &Transformer{
+		table: table,
+		fn:    fn,
+		memo:  make(map[NodeID]NodeID),
+	}
+}
+
+func (transformer *Transformer) Apply(id NodeID) NodeID {
+	if result, ok := transformer.memo[id]; ok {
+		return result
+	}
+
+	node := transformer.table.nodes[id]
+	args := make([]NodeID, len(node.args))
+
+	for i, arg := range node.args {
+		args[i] = transformer.Apply(arg)
+	}
+
+	result := transformer.fn(transformer.table, node.token, args)
+	transformer.memo[id] = result
+	return result
+}
+
+// Subst returns the node with every variable found in values replaced.
+func (table *Table) Subst(id NodeID, values map[string]NodeID) NodeID {
+	return table.NewTransformer(func(table *Table, token Token, args []NodeID) NodeID {
+		if token.Kind == KindVar {
+			if value, ok := values[token.Value.(string)]; ok {
+				return value
+			}
+		}
+		return table.Node(token, args...)
+	}).Apply(id)
+}
+
+// height returns the length of the longest path from the node to a leaf.
+func (table *Table) height(id NodeID, heights map[NodeID]int) int {
+	if h, ok := heights[id]; ok {
+		return h
+	}
+
+	h := 0
+	for _, arg := range table.nodes[id].args {
+		if a := table.height(arg, heights) + 1; a > h {
+			h = a
+		}
+	}
+
+	heights[id] = h
+	return h
+}
@@ -0,0 +1,152 @@
+// Copyright (c) 2017 Przemysław Dobrowolski
+//
+// This file is part of the math-mod, a package for symbolic manipulation
+// of large algebraic expressions.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package math
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Table Object", func() {
+	Context("when expressions are interned", func() {
+		It("should store identical subtrees once", func() {
+			table := NewTable()
+			id := table.Intern(parseTree("(x + 1) * (x + 1) + (x + 1)"))
+
+			Expect(table.Len()).To(Equal(5))
+			Expect(table.TreeSize(id)).To(Equal(11))
+			Expect(table.Args(table.Args(id)[0])).To(Equal([]NodeID{table.Args(id)[1], table.Args(id)[1]}))
+			Expect(table.Expr(id).String()).To(Equal("( x + 1 ) * ( x + 1 ) + ( x + 1 )"))
+		})
+
+		It("should compare by ID", func() {
+			table := NewTable()
+
+			Expect(table.Intern(parseTree("x^2 + y"))).To(Equal(table.Intern(parseTree("x^2 + y"))))
+			Expect(table.Intern(parseTree("x^2 + y"))).NotTo(Equal(table.Intern(parseTree("x^2 + z"))))
+			Expect(table.Intern(parseTree("2"))).NotTo(Equal(table.Intern(parseTree("x2"))))
+		})
+
+		It("should saturate the tree size", func() {
+			table := NewTable()
+			id := table.Intern(parseTree("x"))
+
+			for i := 0; i < 100; i++ {
+				id = table.Node(NewMul(), id, id)
+			}
+
+			Expect(table.Len()).To(Equal(101))
+			Expect(table.TreeSize(id)).To(Equal(int(^uint(0) >> 1)))
+		})
+	})
+
+	Context("when expressions are read into a table", func() {
+		It("should intern postfix without building a tree", func() {
+			table := NewTable()
+			id, err := table.InternPostfix(parseTree("(x + 1) * (x + 1) + (x + 1)").Postfix())
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(table.Len()).To(Equal(5))
+			Expect(id).To(Equal(table.Intern(parseTree("(x + 1) * (x + 1) + (x + 1)"))))
+
+			_, err = table.InternPostfix(Tokens{NewVar("x"), NewPlus()})
+			Expect(err).To(MatchError("missing operand for operator: +"))
+			_, err = table.InternPostfix(Tokens{NewVar("x"), NewVar("y")})
+			Expect(err).To(MatchError("missing operator between operands"))
+		})
+
+		It("should parse infix", func() {
+			table := NewTable()
+			id, err := new(Parser).ParseTable(strings.NewReader("(x + 1)(x + 1) - 3"), table)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(table.Len()).To(Equal(6))
+			Expect(table.Expr(id).String()).To(Equal("( x + 1 ) * ( x + 1 ) - 3"))
+
+			_, err = new(Parser).ParseTable(strings.NewReader("x + * y"), table)
+			Expect(err).To(Equal(&ParseError{Pos: 4, Msg: "missing operand for operator: *"}))
+		})
+	})
+
+	Context("when statistics are collected", func() {
+		It("should agree with the tree", func() {
+			expr := parseTree("(x + y)^2 + x + sin(x + y)")
+			table := NewTable()
+			stats := table.Stats(table.Intern(expr))
+
+			Expect(stats.Distinct).To(Equal(8))
+			stats.Distinct = 0
+			Expect(stats).To(Equal(expr.Stats()))
+		})
+
+		It("should saturate node counts", func() {
+			table := NewTable()
+			id := table.Intern(parseTree("x"))
+
+			for i := 0; i < 100; i++ {
+				id = table.Node(NewMul(), id, id)
+			}
+
+			stats := table.Stats(id)
+			Expect(stats.Distinct).To(Equal(101))
+			Expect(stats.Depth).To(Equal(101))
+			Expect(stats.Nodes).To(Equal(int(^uint(0) >> 1)))
+			Expect(stats.Variables).To(Equal([]string{"x"}))
+		})
+	})
+
+	Context("when interned expressions are transformed", func() {
+		It("should transform each distinct node once", func() {
+			table := NewTable()
+			id := table.Intern(parseTree("x"))
+
+			for i := 0; i < 100; i++ {
+				id = table.Node(NewPlus(), id, id)
+			}
+
+			calls := 0
+			transformer := table.NewTransformer(func(table *Table, token Token, args []NodeID) NodeID {
+				calls++
+				if token.Kind == KindPlus {
+					token = NewMul()
+				}
+				return table.Node(token, args...)
+			})
+
+			result := transformer.Apply(id)
+			Expect(calls).To(Equal(101))
+			Expect(table.Token(result)).To(Equal(NewMul()))
+
+			transformer.Apply(id)
+			Expect(calls).To(Equal(101))
+		})
+
+		It("should substitute variables", func() {
+			table := NewTable()
+			id := table.Subst(table.Intern(parseTree("x * y + x")), map[string]NodeID{"x": table.Intern(parseTree("a - b"))})
+
+			Expect(table.Expr(id).String()).To(Equal("( a - b ) * y + ( a - b )"))
+		})
+	})
+})
@@ -0,0 +1,156 @@
+// Copyright (c) 2017 Przemysław Dobrowolski
+//
+// This file is part of the math-mod, a package for symbolic manipulation
+// of large algebraic expressions.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package math
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Degrees summarises the degrees of a polynomial. With weights, variables
+// not listed have weight 1 and degrees of monomials are weighted.
+type Degrees struct {
+	Total       int            `json:"total"`
+	Variables   map[string]int `json:"variables"`
+	Weights     map[string]int `json:"weights,omitempty"`
+	Homogeneous bool           `json:"homogeneous"`
+	Components  []Component    `json:"components"`
+}
+
+// Component is the number of terms of a homogeneous component.
+type Component struct {
+	Degree int `json:"degree"`
+	Terms  int `json:"terms"`
+}
+
+// CheckWeights verifies that the weights are positive, as weighted degrees
+// of monomials are meant to grow with their exponents.
+func CheckWeights(weights map[string]int) error {
+	var names []string
+
+	for name, weight := range weights {
+		if weight < 1 {
+			names = append(names, name)
+		}
+	}
+
+	if len(names) == 0 {
+		return nil
+	}
+
+	sort.Strings(names)
+	return fmt.Errorf("weight of %v must be positive: %d", names[0], weights[names[0]])
+}
+
+// WeightedDegree returns the sum of exponents times the weights of the
+// variables, which default to 1.
+func (mono Monomial) WeightedDegree(weights map[string]int) (degree int) {
+	for _, power := range mono {
+		weight, ok := weights[power.Var]
+		if !ok {
+			weight = 1
+		}
+		degree += weight * power.Exp
+	}
+	return
+}
+
+// DegreesIn returns the degree of the polynomial in each of its variables.
+func (poly Poly) DegreesIn() map[string]int {
+	degrees := make(map[string]int)
+
+	for _, term := range poly.terms {
+		for _, power := range term.Mono {
+			if power.Exp > degrees[power.Var] {
+				degrees[power.Var] = power.Exp
+			}
+		}
+	}
+	return degrees
+}
+
+// WeightedDegree returns the highest weighted degree of the terms, 0 for the
+// zero polynomial.
+func (poly Poly) WeightedDegree(weights map[string]int) (degree int) {
+	first := true
+
+	for _, term := range poly.terms {
+		if d := term.Mono.WeightedDegree(weights); first || d > degree {
+			degree, first = d, false
+		}
+	}
+	return
+}
+
+// Components splits the polynomial into weighted homogeneous components by
+// descending degree; nil weights give the usual homogeneous components.
+func (poly Poly) Components(weights map[string]int) []Poly {
+	components := make(map[int]Poly)
+	var degrees []int
+
+	for _, term := range poly.terms {
+		degree := term.Mono.WeightedDegree(weights)
+		component, ok := components[degree]
+
+		if !ok {
+			component = NewPolyRing(poly.Ring())
+			components[degree] = component
+			degrees = append(degrees, degree)
+		}
+		component.addTerm(term.Coeff, term.Mono)
+	}
+
+	sort.Sort(sort.Reverse(sort.IntSlice(degrees)))
+	result := make([]Poly, len(degrees))
+
+	for i, degree := range degrees {
+		result[i] = components[degree]
+	}
+	return result
+}
+
+// IsHomogeneous reports whether all terms have the same weighted degree.
+func (poly Poly) IsHomogeneous(weights map[string]int) bool {
+	return len(poly.Components(weights)) <= 1
+}
+
+// Degrees returns the summary of the degrees of the polynomial.
+func (poly Poly) Degrees(weights map[string]int) Degrees {
+	components := poly.Components(weights)
+	degrees := Degrees{
+		Total:       poly.WeightedDegree(weights),
+		Variables:   poly.DegreesIn(),
+		Weights:     weights,
+		Homogeneous: len(components) <= 1,
+		Components:  make([]Component, len(components)),
+	}
+
+	for i, component := range components {
+		degrees.Components[i] = Component{
+			Degree: component.WeightedDegree(weights),
+			Terms:  component.Len(),
+		}
+	}
+	return degrees
+}
@@ -0,0 +1,78 @@
+// Copyright (c) 2017 Przemysław Dobrowolski
+//
+// This file is part of the math-mod, a package for symbolic manipulation
+// of large algebraic expressions.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package math
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Degree Object", func() {
+	Context("when degrees are computed", func() {
+		It("should report them per variable and in total", func() {
+			poly := expandString("x^3 y + x y + y^2 - 5")
+
+			Expect(poly.DegreesIn()).To(Equal(map[string]int{"x": 3, "y": 2}))
+			Expect(poly.WeightedDegree(nil)).To(Equal(4))
+			Expect(poly.WeightedDegree(map[string]int{"y": 3})).To(Equal(6))
+			Expect(NewPoly().WeightedDegree(nil)).To(Equal(0))
+		})
+	})
+
+	Context("when homogeneity is checked", func() {
+		It("should split the components", func() {
+			poly := expandString("x^2 + 2 x y + y^3 + x^3 - 1")
+
+			Expect(poly.IsHomogeneous(nil)).To(BeFalse())
+			Expect(expandString("(x + y)^3").IsHomogeneous(nil)).To(BeTrue())
+
+			components := poly.Components(nil)
+			Expect(components).To(HaveLen(3))
+			Expect(components[0].String()).To(Equal("x ^ 3 + y ^ 3"))
+			Expect(components[1].String()).To(Equal("x ^ 2 + 2 * x * y"))
+			Expect(components[2].String()).To(Equal("-1"))
+
+			degrees := poly.Degrees(nil)
+			Expect(degrees.Total).To(Equal(3))
+			Expect(degrees.Homogeneous).To(BeFalse())
+			Expect(degrees.Components).To(Equal([]Component{{Degree: 3, Terms: 2}, {Degree: 2, Terms: 2}, {Degree: 0, Terms: 1}}))
+		})
+
+		It("should accept weights", func() {
+			weights := map[string]int{"x": 2, "y": 3}
+			poly := expandString("x^3 + y^2 + x^3 y^2")
+
+			Expect(poly.IsHomogeneous(weights)).To(BeFalse())
+			Expect(expandString("x^3 + y^2").IsHomogeneous(weights)).To(BeTrue())
+			Expect(poly.Degrees(weights).Components).To(Equal([]Component{{Degree: 12, Terms: 1}, {Degree: 6, Terms: 2}}))
+		})
+
+		It("should reject weights below 1", func() {
+			Expect(CheckWeights(map[string]int{"x": 2, "y": 1})).To(Succeed())
+			Expect(CheckWeights(nil)).To(Succeed())
+			Expect(CheckWeights(map[string]int{"x": 2, "y": 0})).To(MatchError("weight of y must be positive: 0"))
+			Expect(CheckWeights(map[string]int{"z": -1, "y": -2})).To(MatchError("weight of y must be positive: -2"))
+		})
+	})
+})
@@ -0,0 +1,287 @@
+// Copyright (c) 2017 Przemysław Dobrowolski
+//
+// This file is part of the math-mod, a package for symbolic manipulation
+// of large algebraic expressions.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package math
+
+import (
+	"container/heap"
+	"context"
+	"fmt"
+	"math/big"
+)
+
+// DivMod divides the polynomial by the divisors with respect to the
+// monomial order. It returns quotients and a remainder such that poly is the
+// sum of quos[i]*divisors[i] and rem, and no term of rem is divisible by the
+// leading monomial of a divisor. Polynomials over Z are divided over Q.
+func DivMod(poly Poly, divisors []Poly, order Order) ([]Poly, Poly, error) {
+	return divMod(poly, divisors, order, nil)
+}
+
+// DivModContext works like DivMod, but gives up when the context is done or
+// the limits are exceeded.
+func DivModContext(ctx context.Context, poly Poly, divisors []Poly, order Order, limits Limits) ([]Poly, Poly, error) {
+	return divMod(poly, divisors, order, newGuard(ctx, limits))
+}
+
+func divMod(poly Poly, divisors []Poly, order Order, g *guard) ([]Poly, Poly, error) {
+	field := poly.Ring()
+
+	if !field.IsField() {
+		field = Rationals
+	}
+
+	dividend, err := poly.To(field)
+
+	if err != nil {
+		return nil, Poly{}, err
+	}
+
+	// the dividend is reduced in place, its terms visited in descending order
+	rest := NewPolyRing(field)
+	pending := &monomialHeap{order: order}
+
+	for _, term := range dividend.terms {
+		rest.addTerm(term.Coeff, term.Mono)
+		heap.Push(pending, term.Mono)
+	}
+
+	leads := make([]Term, len(divisors))
+	quos := make([]Poly, len(divisors))
+
+	for i, divisor := range divisors {
+		if divisor.IsZero() {
+			return nil, Poly{}, fmt.Errorf("division by zero")
+		}
+
+		if divisors[i], err = divisor.To(field); err != nil {
+			return nil, Poly{}, err
+		}
+
+		leads[i] = divisors[i].Lead(order)
+		quos[i] = NewPolyRing(field)
+	}
+
+	rem := NewPolyRing(field)
+
+	for pending.Len() > 0 {
+		mono := heap.Pop(pending).(Monomial)
+		term, ok := rest.terms[mono.String()]
+
+		if !ok {
+			continue
+		}
+
+		if err := g.step(); err != nil {
+			return nil, Poly{}, err
+		}
+
+		divided := false
+
+		for i, lead := range leads {
+			factor, ok := term.Mono.Div(lead.Mono)
+
+			if !ok {
+				continue
+			}
+
+			coeff := rest.reduce(new(big.Rat).Quo(term.Coeff, lead.Coeff))
+			quos[i].addTerm(coeff, factor)
+
+			for _, other := range divisors[i].terms {
+				product := other.Mono.Mul(factor)
+				key := product.String()
+				_, present := rest.terms[key]
+
+				rest.addTerm(new(big.Rat).Neg(new(big.Rat).Mul(coeff, other.Coeff)), product)
+
+				if _, ok := rest.terms[key]; ok && !present {
+					heap.Push(pending, product)
+				}
+			}
+
+			if err := g.checkTerms(len(rest.terms)+len(quos[i].terms), coeff, factor); err != nil {
+				return nil, Poly{}, err
+			}
+
+			divided = true
+			break
+		}
+
+		if !divided {
+			rem.addTerm(term.Coeff, term.Mono)
+			delete(rest.terms, mono.String())
+		}
+	}
+
+	return quos, rem, nil
+}
+
+// monomialHeap is a max-heap of monomials in a monomial order.
+type monomialHeap struct {
+	order Order
+	monos []Monomial
+}
+
+func (h *monomialHeap) Len() int {
+	return len(h.monos)
+}
+
+func (h *monomialHeap) Less(i, j int) bool {
+	return h.order.Compare(h.monos[i], h.monos[j]) > 0
+}
+
+func (h *monomialHeap) Swap(i, j int) {
+	h.monos[i], h.monos[j] = h.monos[j], h.monos[i]
+}
+
+func (h *monomialHeap) Push(x interface{}) {
+	h.monos = append(h.monos, x.(Monomial))
+}
+
+func (h *monomialHeap) Pop() interface{} {
+	last := h.monos[len(h.monos)-1]
+	h.monos = h.monos[:len(h.monos)-1]
+	return last
+}
+
+// PseudoDivMod divides a by b as polynomials in the variable without
+// dividing coefficients: with lc the leading coefficient of b and k the
+// difference of degrees plus one, lc^k*a = quo*b + rem, where rem has a lower
+// degree in the variable than b.
+func PseudoDivMod(a, b Poly, name string) (Poly, Poly, error) {
+	return pseudoDivMod(a, b, name, nil)
+}
+
+// PseudoDivModContext works like PseudoDivMod, but gives up when the context
+// is done or the limits are exceeded.
+func PseudoDivModContext(ctx context.Context, a, b Poly, name string, limits Limits) (Poly, Poly, error) {
+	return pseudoDivMod(a, b, name, newGuard(ctx, limits))
+}
+
+func pseudoDivMod(a, b Poly, name string, g *guard) (quo, rem Poly, err error) {
+	if b.IsZero() {
+		return Poly{}, Poly{}, fmt.Errorf("division by zero")
+	}
+
+	ring := a.Ring()
+
+	if b, err = b.To(ring); err != nil {
+		return Poly{}, Poly{}, err
+	}
+
+	degree := b.DegreeIn(name)
+	lead := b.CoeffIn(name, degree)
+	steps := a.DegreeIn(name) - degree + 1
+	quo, rem = NewPolyRing(ring), a
+
+	for !rem.IsZero() && rem.DegreeIn(name) >= degree {
+		if err = g.step(); err != nil {
+			return Poly{}, Poly{}, err
+		}
+
+		remDegree := rem.DegreeIn(name)
+		shift := rem.CoeffIn(name, remDegree).Mul(newPolyVar(ring, name).Pow(remDegree - degree))
+
+		if quo, err = quo.mul(lead, g); err != nil {
+			return Poly{}, Poly{}, err
+		}
+		if quo, err = quo.add(shift, g); err != nil {
+			return Poly{}, Poly{}, err
+		}
+
+		var product Poly
+		if product, err = shift.mul(b, g); err != nil {
+			return Poly{}, Poly{}, err
+		}
+		if rem, err = rem.mul(lead, g); err != nil {
+			return Poly{}, Poly{}, err
+		}
+		if rem, err = rem.add(product.Neg(), g); err != nil {
+			return Poly{}, Poly{}, err
+		}
+		steps--
+	}
+
+	if steps > 0 {
+		var scale Poly
+		if scale, err = lead.pow(steps, g); err != nil {
+			return Poly{}, Poly{}, err
+		}
+		if quo, err = quo.mul(scale, g); err != nil {
+			return Poly{}, Poly{}, err
+		}
+		if rem, err = rem.mul(scale, g); err != nil {
+			return Poly{}, Poly{}, err
+		}
+	}
+
+	return quo, rem, nil
+}
+
+// DivExact returns a/b, or false if b is zero or does not divide a. Over Z
+// the quotient must have integer coefficients.
+func DivExact(a, b Poly) (Poly, bool) {
+	quo, ok, _ := divExact(a, b, nil)
+	return quo, ok
+}
+
+// DivExactContext works like DivExact, but gives up when the context is
+// done or the limits are exceeded.
+func DivExactContext(ctx context.Context, a, b Poly, limits Limits) (Poly, bool, error) {
+	return divExact(a, b, newGuard(ctx, limits))
+}
+
+func divExact(a, b Poly, g *guard) (Poly, bool, error) {
+	if b.IsZero() {
+		return Poly{}, false, nil
+	}
+
+	ring := a.Ring()
+	field := ring
+
+	if !ring.IsField() {
+		field = Rationals
+	}
+
+	a, err := a.To(field)
+
+	if err != nil {
+		return Poly{}, false, nil
+	}
+
+	if b, err = b.To(field); err != nil {
+		return Poly{}, false, nil
+	}
+
+	quo, ok, err := a.quoExact(b, g)
+
+	if !ok || err != nil {
+		return Poly{}, false, err
+	}
+
+	if quo, err = quo.To(ring); err != nil {
+		return Poly{}, false, nil
+	}
+	return quo, true, nil
+}
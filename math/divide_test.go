@@ -0,0 +1,130 @@
+// Copyright (c) 2017 Przemysław Dobrowolski
+//
+// This file is part of the math-mod, a package for symbolic manipulation
+// of large algebraic expressions.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package math
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"context"
+	"strings"
+)
+
+var _ = Describe("Divide Object", func() {
+	Context("when monomials are ordered", func() {
+		It("should follow the order", func() {
+			a, b := expandString("x y^2").Lead(Lex).Mono, expandString("x^2 z").Lead(Lex).Mono
+
+			Expect(Lex.Compare(a, b)).To(Equal(-1))
+			Expect(Grlex.Compare(a, b)).To(Equal(-1))
+			Expect(Grevlex.Compare(a, b)).To(Equal(1))
+			Expect(Grevlex.Compare(a, a)).To(Equal(0))
+
+			_, err := ParseOrder("revlex")
+			Expect(err).Should(HaveOccurred())
+		})
+	})
+
+	Context("when a polynomial is divided by several divisors", func() {
+		It("should leave an irreducible remainder", func() {
+			poly := expandString("x^2 y + x y^2 + y^2")
+			divisors := []Poly{expandString("x y - 1"), expandString("y^2 - 1")}
+
+			quos, rem, err := DivMod(poly, divisors, Lex)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(quos[0].String()).To(Equal("x + y"))
+			Expect(quos[1].String()).To(Equal("1"))
+			Expect(rem.String()).To(Equal("x + y + 1"))
+
+			sum := rem
+			for i, quo := range quos {
+				sum = sum.Add(quo.Mul(divisors[i]))
+			}
+			expected, err := poly.To(Rationals)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(sum.Equal(expected)).To(BeTrue())
+		})
+
+		It("should divide over Q", func() {
+			quos, rem, err := DivMod(expandString("x^3 + 1"), []Poly{expandString("2 x + 1")}, Grlex)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(quos[0].String()).To(Equal("1 / 2 * x ^ 2 - 1 / 4 * x + 1 / 8"))
+			Expect(rem.String()).To(Equal("7 / 8"))
+
+			_, _, err = DivMod(expandString("x"), []Poly{NewPoly()}, Lex)
+			Expect(err).Should(HaveOccurred())
+		})
+	})
+
+	Context("when a pseudo-remainder is computed", func() {
+		It("should not divide coefficients", func() {
+			quo, rem, err := PseudoDivMod(expandString("x^3 + y"), expandString("y x + 1"), "x")
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(quo.String()).To(Equal("x ^ 2 * y ^ 2 - x * y + 1"))
+			Expect(rem.String()).To(Equal("y ^ 4 - 1"))
+		})
+	})
+
+	Context("when an exact division is checked", func() {
+		It("should report divisibility", func() {
+			quo, ok := DivExact(expandString("x^2 - y^2"), expandString("x + y"))
+			Expect(ok).To(BeTrue())
+			Expect(quo.String()).To(Equal("x - y"))
+
+			_, ok = DivExact(expandString("x^2 + 1"), expandString("x + 1"))
+			Expect(ok).To(BeFalse())
+
+			_, ok = DivExact(expandString("x + 1"), expandString("2"))
+			Expect(ok).To(BeFalse())
+		})
+	})
+
+	Context("when a division exceeds the limits", func() {
+		It("should give up", func() {
+			a, b := expandString("(x + y + 1)^6"), expandString("y x + 1")
+
+			_, _, err := PseudoDivModContext(context.Background(), a, b, "x", Limits{MaxTerms: 10})
+			Expect(err).To(MatchError("terms limit exceeded: 10"))
+
+			_, _, err = DivExactContext(context.Background(), a, expandString("x + y + 1"), Limits{MaxTerms: 10})
+			Expect(err).To(MatchError("terms limit exceeded: 10"))
+
+			quo, ok, err := DivExactContext(context.Background(), a, expandString("x + y + 1"), Limits{})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(ok).To(BeTrue())
+			Expect(quo.Equal(expandString("(x + y + 1)^5"))).To(BeTrue())
+		})
+	})
+
+	Context("when expressions are read one per line", func() {
+		It("should report the line of an error", func() {
+			exprs, err := ParseLines(strings.NewReader("x + 1\n\ny^2\n"))
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(exprs).To(HaveLen(2))
+
+			_, err = ParseLines(strings.NewReader("x\n(y\n"))
+			Expect(err).To(MatchError("line 2: position 0: unclosed bracket"))
+		})
+	})
+})
@@ -0,0 +1,168 @@
+// Copyright (c) 2017 Przemysław Dobrowolski
+//
+// This file is part of the math-mod, a package for symbolic manipulation
+// of large algebraic expressions.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package math
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// DotOptions control how an expression tree is drawn in Graphviz DOT.
+type DotOptions struct {
+	// Share draws identical subtrees once, turning the tree into a DAG.
+	Share bool
+	// MaxDepth truncates subtrees below the given depth, 0 means no limit.
+	MaxDepth int
+}
+
+type dotWriter struct {
+	writer  *bufio.Writer
+	options DotOptions
+	nodes   int
+}
+
+// dotRef identifies a drawn node of a table: the node and how many levels
+// below it are drawn, which is less than its height if it is truncated.
+type dotRef struct {
+	id    NodeID
+	depth int
+}
+
+// WriteDot writes the expression tree in Graphviz DOT, one node per token
+// labelled with Token.String and operands ordered from left to right.
+// Truncated subtrees end with an ellipsis node. With Share the tree is
+// interned in a Table and drawn by Table.WriteDot.
+func (expr *Expr) WriteDot(writer io.Writer, options DotOptions) error {
+	if options.Share {
+		table := NewTable()
+		return table.WriteDot(writer, table.Intern(expr), options)
+	}
+
+	w := newDotWriter(writer, options)
+	w.treeNode(expr, 1)
+	return w.close()
+}
+
+// WriteDot writes the interned expression in Graphviz DOT as Expr.WriteDot
+// does with Share, drawing every distinct subtree once.
+func (table *Table) WriteDot(writer io.Writer, id NodeID, options DotOptions) error {
+	w := newDotWriter(writer, options)
+	w.tableNode(table, id, 1, make(map[NodeID]int), make(map[dotRef]int))
+	return w.close()
+}
+
+// Dot returns the expression tree in Graphviz DOT.
+func (expr *Expr) Dot(options DotOptions) string {
+	var builder strings.Builder
+	expr.WriteDot(&builder, options)
+	return builder.String()
+}
+
+func newDotWriter(writer io.Writer, options DotOptions) *dotWriter {
+	w := &dotWriter{
+		writer:  bufio.NewWriter(writer),
+		options: options,
+	}
+
+	w.writer.WriteString("digraph expr {\n")
+	w.writer.WriteString("\tordering=out;\n")
+	w.writer.WriteString("\tnode [shape=box];\n")
+	return w
+}
+
+func (w *dotWriter) close() error {
+	w.writer.WriteString("}\n")
+	return w.writer.Flush()
+}
+
+// truncated reports whether the children of a node at the depth are cut.
+func (w *dotWriter) truncated(depth int, leaf bool) bool {
+	return w.options.MaxDepth > 0 && depth >= w.options.MaxDepth && !leaf
+}
+
+// treeNode writes the subtree and returns the ID of its root.
+func (w *dotWriter) treeNode(expr *Expr, depth int) int {
+	truncated := w.truncated(depth, expr.IsLeaf())
+
+	var children []int
+
+	if !truncated {
+		for _, arg := range expr.Args {
+			children = append(children, w.treeNode(arg, depth+1))
+		}
+	}
+
+	return w.emit(expr.Token, truncated, children)
+}
+
+// tableNode writes the node unless it is already drawn to the same depth
+// and returns the ID of its drawing. Shapes cut by MaxDepth at different
+// depths differ, so a node may be drawn more than once.
+func (w *dotWriter) tableNode(table *Table, id NodeID, depth int, heights map[NodeID]int, drawn map[dotRef]int) int {
+	ref := dotRef{id: id, depth: table.height(id, heights)}
+
+	if w.options.MaxDepth > 0 && w.options.MaxDepth-depth < ref.depth {
+		ref.depth = w.options.MaxDepth - depth
+	}
+
+	if n, ok := drawn[ref]; ok {
+		return n
+	}
+
+	args := table.Args(id)
+	truncated := w.truncated(depth, len(args) == 0)
+
+	var children []int
+
+	if !truncated {
+		for _, arg := range args {
+			children = append(children, w.tableNode(table, arg, depth+1, heights, drawn))
+		}
+	}
+
+	n := w.emit(table.Token(id), truncated, children)
+	drawn[ref] = n
+	return n
+}
+
+// emit writes a node with edges to its drawn children and returns its ID.
+func (w *dotWriter) emit(token Token, truncated bool, children []int) int {
+	id := w.nodes
+	w.nodes++
+
+	fmt.Fprintf(w.writer, "\tn%d [label=%q];\n", id, token.String())
+
+	if truncated {
+		fmt.Fprintf(w.writer, "\tn%d_more [label=\"...\", shape=none];\n", id)
+		fmt.Fprintf(w.writer, "\tn%d -> n%d_more [style=dashed];\n", id, id)
+	}
+
+	for _, child := range children {
+		fmt.Fprintf(w.writer, "\tn%d -> n%d;\n", id, child)
+	}
+
+	return id
+}
@@ -0,0 +1,85 @@
+// Copyright (c) 2017 Przemysław Dobrowolski
+//
+// This file is part of the math-mod, a package for symbolic manipulation
+// of large algebraic expressions.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package math
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Dot Object", func() {
+	Context("when a tree is drawn", func() {
+		It("should draw every node", func() {
+			Expect(parseTree("x * x + 2").Dot(DotOptions{})).To(Equal(`digraph expr {
+	ordering=out;
+	node [shape=box];
+	n0 [label="x"];
+	n1 [label="x"];
+	n2 [label="*"];
+	n2 -> n0;
+	n2 -> n1;
+	n3 [label="2"];
+	n4 [label="+"];
+	n4 -> n2;
+	n4 -> n3;
+}
+`))
+		})
+
+		It("should share identical subtrees", func() {
+			dot := parseTree("(x + 1) * (x + 1) + x").Dot(DotOptions{Share: true})
+
+			Expect(strings.Count(dot, "[label=")).To(Equal(5))
+			Expect(dot).To(ContainSubstring("n3 -> n2;\n\tn3 -> n2;"))
+		})
+
+		It("should draw a table", func() {
+			table := NewTable()
+			id := table.Intern(parseTree("(x + 1) * (x + 1) + x"))
+
+			var builder strings.Builder
+			Expect(table.WriteDot(&builder, id, DotOptions{})).To(Succeed())
+			Expect(builder.String()).To(Equal(parseTree("(x + 1) * (x + 1) + x").Dot(DotOptions{Share: true})))
+		})
+
+		It("should share subtrees truncated alike", func() {
+			dot := parseTree("((a + b) + c) * (a + b)").Dot(DotOptions{Share: true, MaxDepth: 3})
+
+			Expect(strings.Count(dot, `[label="+"]`)).To(Equal(3))
+			Expect(strings.Count(dot, `[label="a"]`)).To(Equal(1))
+			Expect(strings.Count(dot, "_more")).To(Equal(2))
+		})
+
+		It("should truncate deep subtrees", func() {
+			dot := parseTree("(a + b) * c").Dot(DotOptions{MaxDepth: 2})
+
+			Expect(dot).To(ContainSubstring(`n0 [label="+"];`))
+			Expect(dot).To(ContainSubstring(`n0 -> n0_more [style=dashed];`))
+			Expect(dot).NotTo(ContainSubstring(`label="a"`))
+			Expect(dot).To(ContainSubstring(`label="c"`))
+		})
+	})
+})
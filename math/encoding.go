@@ -0,0 +1,344 @@
+// Copyright (c) 2017 Przemysław Dobrowolski
+//
+// This file is part of the math-mod, a package for symbolic manipulation
+// of large algebraic expressions.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package math
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math/big"
+)
+
+var kindNames = map[Kind]string{
+	KindInt:   "int",
+	KindVar:   "var",
+	KindPlus:  "plus",
+	KindMinus: "minus",
+	KindMul:   "mul",
+	KindPow:   "pow",
+	KindOpen:  "open",
+	KindClose: "close",
+	KindFunc:  "func",
+	KindDiv:   "div",
+}
+
+func (kind Kind) String() string {
+	if name, ok := kindNames[kind]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("Kind(%d)", int(kind))
+}
+
+func ParseKind(name string) (Kind, error) {
+	for kind, kindName := range kindNames {
+		if kindName == name {
+			return kind, nil
+		}
+	}
+
+	return 0, fmt.Errorf("invalid token kind: %v", name)
+}
+
+func (kind Kind) MarshalJSON() ([]byte, error) {
+	if _, ok := kindNames[kind]; !ok {
+		return nil, fmt.Errorf("invalid token kind: %d", int(kind))
+	}
+
+	return json.Marshal(kind.String())
+}
+
+func (kind *Kind) UnmarshalJSON(data []byte) (err error) {
+	var name string
+
+	if err = json.Unmarshal(data, &name); err != nil {
+		return err
+	}
+
+	*kind, err = ParseKind(name)
+	return
+}
+
+// jsonToken is the JSON form of a token. Integers are stored as JSON numbers
+// of arbitrary length, variables and functions as strings.
+type jsonToken struct {
+	Kind  Kind            `json:"kind"`
+	Value json.RawMessage `json:"value,omitempty"`
+}
+
+func (token Token) MarshalJSON() ([]byte, error) {
+	value, err := token.encodeValue()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(jsonToken{Kind: token.Kind, Value: value})
+}
+
+func (token Token) encodeValue() (json.RawMessage, error) {
+	switch token.Kind {
+	case KindInt:
+		return json.RawMessage(token.BigInt().String()), nil
+	case KindVar, KindFunc:
+		return json.Marshal(token.Value.(string))
+	}
+
+	return nil, nil
+}
+
+func (token *Token) UnmarshalJSON(data []byte) error {
+	var raw jsonToken
+
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+
+	return token.decodeValue(raw.Kind, raw.Value)
+}
+
+func (token *Token) decodeValue(kind Kind, value json.RawMessage) error {
+	switch kind {
+	case KindInt:
+		var number json.Number
+		if err := json.Unmarshal(value, &number); err != nil {
+			return fmt.Errorf("invalid integer value: %s", value)
+		}
+		i, ok := new(big.Int).SetString(number.String(), 10)
+		if !ok {
+			return fmt.Errorf("invalid integer value: %s", value)
+		}
+		*token = NewBigInt(i)
+	case KindVar, KindFunc:
+		var name string
+		if err := json.Unmarshal(value, &name); err != nil || name == "" {
+			return fmt.Errorf("invalid %v name: %s", kind, value)
+		}
+		*token = Token{Kind: kind, Value: name}
+	default:
+		if len(value) != 0 {
+			return fmt.Errorf("unexpected value for %v: %s", kind, value)
+		}
+		*token = Token{Kind: kind}
+	}
+
+	return nil
+}
+
+// jsonExpr is the JSON form of a tree node: a token with its operands.
+type jsonExpr struct {
+	Kind  Kind            `json:"kind"`
+	Value json.RawMessage `json:"value,omitempty"`
+	Args  []*Expr         `json:"args,omitempty"`
+}
+
+func (expr *Expr) MarshalJSON() ([]byte, error) {
+	value, err := expr.Token.encodeValue()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(jsonExpr{Kind: expr.Token.Kind, Value: value, Args: expr.Args})
+}
+
+func (expr *Expr) UnmarshalJSON(data []byte) error {
+	var node jsonExpr
+
+	if err := json.Unmarshal(data, &node); err != nil {
+		return err
+	}
+
+	if err := expr.Token.decodeValue(node.Kind, node.Value); err != nil {
+		return err
+	}
+
+	if arity(node.Kind) != len(node.Args) {
+		return fmt.Errorf("invalid number of operands for %v: %d", node.Kind, len(node.Args))
+	}
+
+	for _, arg := range node.Args {
+		if arg == nil {
+			return fmt.Errorf("missing operand for %v", node.Kind)
+		}
+	}
+
+	expr.Args = node.Args
+	return nil
+}
+
+// arity returns the number of operands a node of the kind takes in a tree,
+// or -1 if the kind cannot occur in a tree.
+func arity(kind Kind) int {
+	switch kind {
+	case KindInt, KindVar:
+		return 0
+	case KindFunc:
+		return 1
+	case KindPlus, KindMinus, KindMul, KindDiv, KindPow:
+		return 2
+	}
+
+	return -1
+}
+
+// binaryMagic starts every binary encoded token stream.
+var binaryMagic = []byte("mm\x01")
+
+// kindBigInt tags integers that do not fit into int64 in the binary form.
+const kindBigInt = 0x80
+
+// MarshalBinary encodes the tokens compactly: a kind byte per token followed
+// by a zig-zag varint for integers, or a length prefixed string for names.
+// Integers outside of int64 store their sign and magnitude bytes instead.
+func (tokens Tokens) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+	var scratch [binary.MaxVarintLen64]byte
+
+	buf.Write(binaryMagic)
+
+	for _, token := range tokens {
+		if _, ok := kindNames[token.Kind]; !ok {
+			return nil, fmt.Errorf("invalid token kind: %d", int(token.Kind))
+		}
+
+		switch token.Kind {
+		case KindInt:
+			if value, ok := token.Value.(int64); ok {
+				buf.WriteByte(byte(token.Kind))
+				buf.Write(scratch[:binary.PutVarint(scratch[:], value)])
+			} else {
+				value := token.BigInt()
+				buf.WriteByte(byte(token.Kind) | kindBigInt)
+				buf.WriteByte(byte(value.Sign() + 1))
+				writeBytes(&buf, value.Bytes())
+			}
+		case KindVar, KindFunc:
+			buf.WriteByte(byte(token.Kind))
+			writeBytes(&buf, []byte(token.Value.(string)))
+		default:
+			buf.WriteByte(byte(token.Kind))
+		}
+	}
+
+	return buf.Bytes(), nil
+}
+
+func writeBytes(buf *bytes.Buffer, data []byte) {
+	var scratch [binary.MaxVarintLen64]byte
+
+	buf.Write(scratch[:binary.PutUvarint(scratch[:], uint64(len(data)))])
+	buf.Write(data)
+}
+
+func (tokens *Tokens) UnmarshalBinary(data []byte) error {
+	reader := bytes.NewReader(data)
+	magic := make([]byte, len(binaryMagic))
+
+	if n, _ := reader.Read(magic); n != len(magic) || !bytes.Equal(magic, binaryMagic) {
+		return fmt.Errorf("invalid binary header")
+	}
+
+	result := Tokens{}
+
+	for reader.Len() > 0 {
+		tag, _ := reader.ReadByte()
+		kind := Kind(tag &^ kindBigInt)
+
+		if _, ok := kindNames[kind]; !ok {
+			return fmt.Errorf("invalid token kind: %d", int(kind))
+		}
+
+		switch {
+		case tag&kindBigInt != 0 && kind == KindInt:
+			sign, err := reader.ReadByte()
+			if err != nil || sign > 2 {
+				return fmt.Errorf("invalid integer sign")
+			}
+			magnitude, err := readBytes(reader)
+			if err != nil {
+				return err
+			}
+			value := new(big.Int).SetBytes(magnitude)
+			if sign == 0 {
+				value.Neg(value)
+			}
+			result = append(result, NewBigInt(value))
+		case tag&kindBigInt != 0:
+			return fmt.Errorf("invalid token tag: %d", tag)
+		case kind == KindInt:
+			value, err := binary.ReadVarint(reader)
+			if err != nil {
+				return fmt.Errorf("truncated integer")
+			}
+			result = append(result, NewInt(value))
+		case kind == KindVar || kind == KindFunc:
+			name, err := readBytes(reader)
+			if err != nil {
+				return err
+			}
+			result = append(result, Token{Kind: kind, Value: string(name)})
+		default:
+			result = append(result, Token{Kind: kind})
+		}
+	}
+
+	*tokens = result
+	return nil
+}
+
+func readBytes(reader *bytes.Reader) ([]byte, error) {
+	length, err := binary.ReadUvarint(reader)
+
+	if err != nil || length > uint64(reader.Len()) {
+		return nil, fmt.Errorf("truncated string")
+	}
+
+	data := make([]byte, length)
+	reader.Read(data)
+	return data, nil
+}
+
+// MarshalBinary encodes the tree as its postfix token stream.
+func (expr *Expr) MarshalBinary() ([]byte, error) {
+	return expr.Postfix().MarshalBinary()
+}
+
+func (expr *Expr) UnmarshalBinary(data []byte) error {
+	var postfix Tokens
+
+	if err := postfix.UnmarshalBinary(data); err != nil {
+		return err
+	}
+
+	result, err := ToTree(postfix)
+
+	if err != nil {
+		return err
+	}
+
+	*expr = *result
+	return nil
+}
@@ -0,0 +1,101 @@
+// Copyright (c) 2017 Przemysław Dobrowolski
+//
+// This file is part of the math-mod, a package for symbolic manipulation
+// of large algebraic expressions.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package math
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"encoding/json"
+	"math/big"
+)
+
+var _ = Describe("Encoding Object", func() {
+	var huge Token
+
+	BeforeEach(func() {
+		value, _ := new(big.Int).SetString("-123456789012345678901234567890", 10)
+		huge = NewBigInt(value)
+	})
+
+	Context("when tokens are encoded in JSON", func() {
+		It("should use kind names and raw values", func() {
+			data, err := json.Marshal(Tokens{NewInt(7), NewVar("x"), NewPlus(), huge})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(string(data)).To(Equal(`[{"kind":"int","value":7},{"kind":"var","value":"x"},{"kind":"plus"},{"kind":"int","value":-123456789012345678901234567890}]`))
+
+			var tokens Tokens
+			Expect(json.Unmarshal(data, &tokens)).To(Succeed())
+			Expect(tokens).To(Equal(Tokens{NewInt(7), NewVar("x"), NewPlus(), huge}))
+		})
+	})
+
+	Context("when a tree is encoded in JSON", func() {
+		It("should round-trip", func() {
+			expr := parseTree("2 sin(x)^y - 3")
+
+			data, err := json.Marshal(expr)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(string(data)).To(HavePrefix(`{"kind":"minus","args":[{"kind":"mul","args":[{"kind":"int","value":2},`))
+
+			var decoded Expr
+			Expect(json.Unmarshal(data, &decoded)).To(Succeed())
+			Expect(decoded.Equal(expr)).To(BeTrue())
+		})
+	})
+
+	Context("when a JSON tree has the wrong number of operands", func() {
+		It("should fail", func() {
+			var decoded Expr
+			Expect(json.Unmarshal([]byte(`{"kind":"plus","args":[{"kind":"int","value":1}]}`), &decoded)).ShouldNot(Succeed())
+			Expect(json.Unmarshal([]byte(`{"kind":"open"}`), &decoded)).ShouldNot(Succeed())
+			Expect(json.Unmarshal([]byte(`{"kind":"int","value":"x"}`), &decoded)).ShouldNot(Succeed())
+		})
+	})
+
+	Context("when tokens are encoded in binary", func() {
+		It("should round-trip", func() {
+			tokens := Tokens{NewInt(-300), NewVar("alpha"), NewFunc("cos"), huge, NewOpen(), NewPow()}
+
+			data, err := tokens.MarshalBinary()
+			Expect(err).ShouldNot(HaveOccurred())
+
+			var decoded Tokens
+			Expect(decoded.UnmarshalBinary(data)).To(Succeed())
+			Expect(decoded).To(Equal(tokens))
+		})
+	})
+
+	Context("when binary data is truncated", func() {
+		It("should fail", func() {
+			data, err := parseTree("x + y").MarshalBinary()
+			Expect(err).ShouldNot(HaveOccurred())
+
+			var decoded Expr
+			Expect(decoded.UnmarshalBinary(data)).To(Succeed())
+			Expect(decoded.UnmarshalBinary(data[:len(data)-3])).ShouldNot(Succeed())
+			Expect(decoded.UnmarshalBinary(data[1:])).ShouldNot(Succeed())
+		})
+	})
+})
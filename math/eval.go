@@ -0,0 +1,234 @@
+// Copyright (c) 2017 Przemysław Dobrowolski
+//
+// This file is part of the math-mod, a package for symbolic manipulation
+// of large algebraic expressions.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package math
+
+import (
+	"context"
+	"fmt"
+	gomath "math"
+	"math/big"
+)
+
+var funcValues = map[string]func(float64) float64{
+	"sin":  gomath.Sin,
+	"cos":  gomath.Cos,
+	"tan":  gomath.Tan,
+	"exp":  gomath.Exp,
+	"log":  gomath.Log,
+	"sqrt": gomath.Sqrt,
+}
+
+// Eval computes the value of the expression in floating point, taking the
+// values of variables from values.
+func Eval(expr *Expr, values map[string]float64) (float64, error) {
+	switch expr.Token.Kind {
+	case KindInt:
+		value, _ := new(big.Float).SetInt(expr.Token.BigInt()).Float64()
+		return value, nil
+	case KindVar:
+		name := expr.Token.Value.(string)
+		if value, ok := values[name]; ok {
+			return value, nil
+		}
+		return 0, fmt.Errorf("unbound variable: %v", name)
+	case KindFunc:
+		name := expr.Token.Value.(string)
+		fn, ok := funcValues[name]
+		if !ok {
+			return 0, fmt.Errorf("unknown function: %v", name)
+		}
+		arg, err := Eval(expr.Args[0], values)
+		if err != nil {
+			return 0, err
+		}
+		return fn(arg), nil
+	}
+
+	if len(expr.Args) != 2 {
+		return 0, fmt.Errorf("unexpected token: %v", expr.Token)
+	}
+
+	left, err := Eval(expr.Args[0], values)
+
+	if err != nil {
+		return 0, err
+	}
+
+	right, err := Eval(expr.Args[1], values)
+
+	if err != nil {
+		return 0, err
+	}
+
+	switch expr.Token.Kind {
+	case KindPlus:
+		return left + right, nil
+	case KindMinus:
+		return left - right, nil
+	case KindMul:
+		return left * right, nil
+	case KindDiv:
+		return left / right, nil
+	case KindPow:
+		return gomath.Pow(left, right), nil
+	}
+
+	return 0, fmt.Errorf("unexpected token: %v", expr.Token)
+}
+
+// EvalRing computes the exact value of the expression in the ring, taking
+// the values of variables from values. Exponents must evaluate to integers;
+// negative ones are allowed in fields.
+func EvalRing(expr *Expr, ring Ring, values map[string]*big.Rat) (*big.Rat, error) {
+	return evalRing(expr, ring, values, nil)
+}
+
+// EvalRingContext works like EvalRing, but gives up when the context is done
+// or a power outgrows the memory limit.
+func EvalRingContext(ctx context.Context, expr *Expr, ring Ring, values map[string]*big.Rat, limits Limits) (*big.Rat, error) {
+	return evalRing(expr, ring, values, newGuard(ctx, limits))
+}
+
+func evalRing(expr *Expr, ring Ring, values map[string]*big.Rat, g *guard) (*big.Rat, error) {
+	switch expr.Token.Kind {
+	case KindInt:
+		value, ok := ring.Reduce(new(big.Rat).SetInt(expr.Token.BigInt()))
+		if !ok {
+			return nil, fmt.Errorf("integer %v does not belong to %v", expr.Token, ring)
+		}
+		return value, nil
+	case KindVar:
+		name := expr.Token.Value.(string)
+		value, ok := values[name]
+		if !ok {
+			return nil, fmt.Errorf("unbound variable: %v", name)
+		}
+		if value, ok = ring.Reduce(value); !ok {
+			return nil, fmt.Errorf("value of %v does not belong to %v", name, ring)
+		}
+		return value, nil
+	case KindFunc:
+		return nil, fmt.Errorf("cannot evaluate function exactly: %v", expr.Token)
+	}
+
+	if len(expr.Args) != 2 {
+		return nil, fmt.Errorf("unexpected token: %v", expr.Token)
+	}
+
+	left, err := evalRing(expr.Args[0], ring, values, g)
+
+	if err != nil {
+		return nil, err
+	}
+
+	// exponents are integers whatever the ring
+	rightRing := ring
+	if expr.Token.Kind == KindPow {
+		rightRing = Rationals
+	}
+
+	right, err := evalRing(expr.Args[1], rightRing, values, g)
+
+	if err != nil {
+		return nil, err
+	}
+
+	result := new(big.Rat)
+
+	switch expr.Token.Kind {
+	case KindPlus:
+		result.Add(left, right)
+	case KindMinus:
+		result.Sub(left, right)
+	case KindMul:
+		result.Mul(left, right)
+	case KindDiv:
+		if right.Sign() == 0 {
+			return nil, fmt.Errorf("division by zero")
+		}
+		result.Quo(left, right)
+	case KindPow:
+		return powRing(ring, left, right, g)
+	default:
+		return nil, fmt.Errorf("unexpected token: %v", expr.Token)
+	}
+
+	value, ok := ring.Reduce(result)
+
+	if !ok {
+		return nil, fmt.Errorf("quotient %v does not belong to %v", result.RatString(), ring)
+	}
+
+	return value, nil
+}
+
+// powRing raises base to an integer power by repeated squaring. Each squaring
+// doubles the size of the value over Z and Q, so the guard is checked on
+// every one.
+func powRing(ring Ring, base, exp *big.Rat, g *guard) (*big.Rat, error) {
+	if !exp.IsInt() {
+		return nil, fmt.Errorf("exponent is not an integer: %v", exp.RatString())
+	}
+
+	n := new(big.Int).Set(exp.Num())
+
+	if n.Sign() < 0 {
+		if !ring.IsField() || base.Sign() == 0 {
+			return nil, fmt.Errorf("cannot raise %v to a negative power in %v", base.RatString(), ring)
+		}
+		base = inverse(ring, base)
+		n.Neg(n)
+	}
+
+	// by Fermat's little theorem exponents of non-zero elements of Z/pZ
+	// only matter modulo p-1
+	if modular, ok := ring.(Modular); ok && base.Sign() != 0 {
+		n.Mod(n, new(big.Int).Sub(modular.p, big.NewInt(1)))
+	}
+
+	result := big.NewRat(1, 1)
+	square := new(big.Rat).Set(base)
+
+	for i := 0; i < n.BitLen(); i++ {
+		if n.Bit(i) == 1 {
+			result, _ = ring.Reduce(result.Mul(result, square))
+		}
+
+		if i+1 < n.BitLen() {
+			square, _ = ring.Reduce(square.Mul(square, square))
+		}
+
+		if err := g.check(); err != nil {
+			return nil, err
+		}
+		if err := g.checkTerms(1, result, nil); err != nil {
+			return nil, err
+		}
+		if err := g.checkTerms(1, square, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
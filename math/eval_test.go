@@ -0,0 +1,56 @@
+// Copyright (c) 2017 Przemysław Dobrowolski
+//
+// This file is part of the math-mod, a package for symbolic manipulation
+// of large algebraic expressions.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package math
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Eval Object", func() {
+	Context("when all variables are bound", func() {
+		It("should compute the value", func() {
+			value, err := Eval(parseTree("2 x^3 - (y - 1) + cos(0)"), map[string]float64{"x": 1.5, "y": 4})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(value).To(BeNumerically("~", 4.75))
+		})
+	})
+
+	Context("when a variable is not bound", func() {
+		It("should fail", func() {
+			_, err := Eval(parseTree("x + y"), map[string]float64{"x": 1})
+			Expect(err).To(MatchError("unbound variable: y"))
+		})
+	})
+
+	Context("when statistics are collected", func() {
+		It("should count nodes by kind", func() {
+			stats := parseTree("(x + y)^2 + x").Stats()
+			Expect(stats.Nodes).To(Equal(7))
+			Expect(stats.Depth).To(Equal(4))
+			Expect(stats.Kinds).To(Equal(map[string]int{"plus": 2, "pow": 1, "var": 3, "int": 1}))
+			Expect(stats.Variables).To(Equal([]string{"x", "y"}))
+		})
+	})
+})
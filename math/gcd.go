@@ -0,0 +1,359 @@
+// Copyright (c) 2017 Przemysław Dobrowolski
+//
+// This file is part of the math-mod, a package for symbolic manipulation
+// of large algebraic expressions.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package math
+
+import (
+	"context"
+	"math/big"
+)
+
+// GCD returns the greatest common divisor of two polynomials over the same
+// ring. Over Z/pZ the result is monic; over Z and Q it has coprime integer
+// coefficients and a positive leading coefficient, and over Z it also
+// carries the common content of a and b.
+func GCD(a, b Poly) (Poly, error) {
+	return gcd(a, b, nil)
+}
+
+// GCDContext works like GCD, but gives up when the context is done or the
+// limits are exceeded.
+func GCDContext(ctx context.Context, a, b Poly, limits Limits) (Poly, error) {
+	return gcd(a, b, newGuard(ctx, limits))
+}
+
+func gcd(a, b Poly, g *guard) (Poly, error) {
+	ring := a.Ring()
+	field := ring
+
+	if !ring.IsField() {
+		field = Rationals
+	}
+
+	a, err := a.To(field)
+
+	if err != nil {
+		return Poly{}, err
+	}
+
+	b, err = b.To(field)
+
+	if err != nil {
+		return Poly{}, err
+	}
+
+	result, err := gcdField(a, b, g)
+
+	if err != nil {
+		return Poly{}, err
+	}
+
+	if _, ok := ring.(Modular); ok {
+		return result.monic(), nil
+	}
+
+	result = result.primitive()
+
+	if ring == Integers {
+		content := new(big.Rat).SetInt(new(big.Int).GCD(nil, nil, a.content(), b.content()))
+		result = result.Mul(newPolyConst(field, content))
+	}
+
+	return result.To(ring)
+}
+
+// gcdField computes a gcd of polynomials over a field, up to a constant
+// factor, with the primitive polynomial remainder sequence in the first
+// variable. The contents, which do not depend on it, are handled
+// recursively.
+func gcdField(a, b Poly, g *guard) (Poly, error) {
+	switch {
+	case a.IsZero():
+		return b, nil
+	case b.IsZero():
+		return a, nil
+	}
+
+	name := firstVar(a, b)
+
+	if name == "" {
+		return newPolyConst(a.Ring(), big.NewRat(1, 1)), nil
+	}
+
+	contentA, err := a.contentIn(name, g)
+
+	if err != nil {
+		return Poly{}, err
+	}
+
+	contentB, err := b.contentIn(name, g)
+
+	if err != nil {
+		return Poly{}, err
+	}
+
+	content, err := gcdField(contentA, contentB, g)
+
+	if err != nil {
+		return Poly{}, err
+	}
+
+	if a, _, err = a.quoExact(contentA, g); err != nil {
+		return Poly{}, err
+	}
+
+	if b, _, err = b.quoExact(contentB, g); err != nil {
+		return Poly{}, err
+	}
+
+	if a.DegreeIn(name) < b.DegreeIn(name) {
+		a, b = b, a
+	}
+
+	for !b.IsZero() {
+		rem, err := a.premIn(b, name, g)
+
+		if err != nil {
+			return Poly{}, err
+		}
+
+		if rem, err = rem.primitiveIn(name, g); err != nil {
+			return Poly{}, err
+		}
+
+		a, b = b, rem
+	}
+
+	if a, err = a.primitiveIn(name, g); err != nil {
+		return Poly{}, err
+	}
+
+	return content.mul(a, g)
+}
+
+// firstVar returns the alphabetically first variable of the polynomials, or
+// "" if all of them are constant.
+func firstVar(polys ...Poly) (name string) {
+	for _, poly := range polys {
+		for _, term := range poly.terms {
+			if len(term.Mono) > 0 && (name == "" || term.Mono[0].Var < name) {
+				name = term.Mono[0].Var
+			}
+		}
+	}
+	return
+}
+
+// DegreeIn returns the degree of the polynomial in the variable.
+func (poly Poly) DegreeIn(name string) (degree int) {
+	for _, term := range poly.terms {
+		if exp := term.Mono.Exp(name); exp > degree {
+			degree = exp
+		}
+	}
+	return
+}
+
+// CoeffIn returns the coefficient of name^exp, a polynomial in the other
+// variables.
+func (poly Poly) CoeffIn(name string, exp int) Poly {
+	result := NewPolyRing(poly.Ring())
+
+	for _, term := range poly.terms {
+		if term.Mono.Exp(name) == exp {
+			result.addTerm(term.Coeff, term.Mono.without(name))
+		}
+	}
+	return result
+}
+
+// contentIn returns the gcd of the coefficients of the powers of name.
+func (poly Poly) contentIn(name string, g *guard) (Poly, error) {
+	content := NewPolyRing(poly.Ring())
+
+	for exp := poly.DegreeIn(name); exp >= 0; exp-- {
+		if coeff := poly.CoeffIn(name, exp); !coeff.IsZero() {
+			var err error
+
+			if content, err = gcdField(content, coeff, g); err != nil {
+				return Poly{}, err
+			}
+		}
+	}
+	return content, nil
+}
+
+func (poly Poly) primitiveIn(name string, g *guard) (Poly, error) {
+	if poly.IsZero() {
+		return poly, nil
+	}
+
+	content, err := poly.contentIn(name, g)
+
+	if err != nil {
+		return Poly{}, err
+	}
+
+	result, _, err := poly.quoExact(content, g)
+	return result, err
+}
+
+// premIn returns the pseudo-remainder of the polynomial divided by divisor
+// as polynomials in name: the remainder of lc^k*poly, where lc is the
+// leading coefficient of divisor, which needs no division of coefficients.
+func (poly Poly) premIn(divisor Poly, name string, g *guard) (Poly, error) {
+	degree := divisor.DegreeIn(name)
+	lead := divisor.CoeffIn(name, degree)
+	rem := poly
+
+	for !rem.IsZero() && rem.DegreeIn(name) >= degree {
+		if err := g.step(); err != nil {
+			return Poly{}, err
+		}
+
+		remDegree := rem.DegreeIn(name)
+		shift := rem.CoeffIn(name, remDegree).Mul(newPolyVar(poly.Ring(), name).Pow(remDegree - degree))
+
+		product, err := shift.mul(divisor, g)
+
+		if err != nil {
+			return Poly{}, err
+		}
+
+		if rem, err = rem.mul(lead, g); err != nil {
+			return Poly{}, err
+		}
+
+		if rem, err = rem.add(product.Neg(), g); err != nil {
+			return Poly{}, err
+		}
+	}
+
+	return rem, nil
+}
+
+// quoExact divides by a non-zero polynomial over a field within the limits
+// of the guard, reporting false if the division leaves a remainder.
+func (poly Poly) quoExact(divisor Poly, g *guard) (Poly, bool, error) {
+	ring := poly.Ring()
+	lead := divisor.Lead(Lex)
+	inv := inverse(ring, lead.Coeff)
+	quo := NewPolyRing(ring)
+	rem := poly
+
+	for !rem.IsZero() {
+		if err := g.step(); err != nil {
+			return Poly{}, false, err
+		}
+
+		term := rem.Lead(Lex)
+		mono, ok := term.Mono.Div(lead.Mono)
+
+		if !ok {
+			return Poly{}, false, nil
+		}
+
+		factor := NewPolyRing(ring)
+		factor.addTerm(new(big.Rat).Mul(term.Coeff, inv), mono)
+		quo = quo.Add(factor)
+
+		product, err := factor.mul(divisor, g)
+
+		if err != nil {
+			return Poly{}, false, err
+		}
+
+		if rem, err = rem.add(product.Neg(), g); err != nil {
+			return Poly{}, false, err
+		}
+	}
+
+	return quo, true, nil
+}
+
+// monic returns the polynomial over a field divided by its leading
+// coefficient.
+func (poly Poly) monic() Poly {
+	if poly.IsZero() {
+		return poly
+	}
+
+	result, _ := poly.divConst(poly.Terms()[0].Coeff)
+	return result
+}
+
+// content returns the gcd of the coefficients of a polynomial over Z.
+func (poly Poly) content() *big.Int {
+	content := new(big.Int)
+
+	for _, term := range poly.terms {
+		content.GCD(nil, nil, content, new(big.Int).Abs(term.Coeff.Num()))
+	}
+
+	return content
+}
+
+// primitive returns the polynomial scaled to coprime integer coefficients
+// with a positive leading coefficient.
+func (poly Poly) primitive() Poly {
+	if poly.IsZero() {
+		return poly
+	}
+
+	scale := integerScale(poly)
+
+	if poly.Terms()[0].Coeff.Sign() < 0 {
+		scale.Neg(scale)
+	}
+
+	return poly.scale(scale)
+}
+
+// integerScale returns the positive factor that brings the coefficients of
+// all the polynomials to coprime integers.
+func integerScale(polys ...Poly) *big.Rat {
+	lcm, gcd := big.NewInt(1), new(big.Int)
+
+	for _, poly := range polys {
+		for _, term := range poly.terms {
+			den := term.Coeff.Denom()
+			lcm.Div(new(big.Int).Mul(lcm, den), new(big.Int).GCD(nil, nil, lcm, den))
+			gcd.GCD(nil, nil, gcd, new(big.Int).Abs(term.Coeff.Num()))
+		}
+	}
+
+	if gcd.Sign() == 0 {
+		return big.NewRat(1, 1)
+	}
+	return new(big.Rat).SetFrac(lcm, gcd)
+}
+
+// scale multiplies every coefficient by a constant of the ring.
+func (poly Poly) scale(factor *big.Rat) Poly {
+	result := NewPolyRing(poly.Ring())
+
+	for _, term := range poly.terms {
+		result.addTerm(poly.reduce(new(big.Rat).Mul(term.Coeff, factor)), term.Mono)
+	}
+	return result
+}
@@ -0,0 +1,247 @@
+// Copyright (c) 2017 Przemysław Dobrowolski
+//
+// This file is part of the math-mod, a package for symbolic manipulation
+// of large algebraic expressions.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package math
+
+import (
+	"context"
+	"sort"
+)
+
+// Groebner returns the reduced Gröbner basis of the ideal generated by the
+// polynomials with respect to the monomial order, computed with Buchberger's
+// algorithm. Polynomials over Z are taken over Q. The basis polynomials are
+// monic and sorted by ascending leading monomials.
+func Groebner(polys []Poly, order Order) ([]Poly, error) {
+	return groebner(polys, order, nil)
+}
+
+// GroebnerContext works like Groebner, but gives up when the context is done
+// or the limits are exceeded.
+func GroebnerContext(ctx context.Context, polys []Poly, order Order, limits Limits) ([]Poly, error) {
+	return groebner(polys, order, newGuard(ctx, limits))
+}
+
+// NormalForm returns the remainder of the polynomial divided by a Gröbner
+// basis, which is the same for all polynomials equal modulo the ideal.
+func NormalForm(poly Poly, basis []Poly, order Order) (Poly, error) {
+	_, rem, err := divMod(poly, basis, order, nil)
+	return rem, err
+}
+
+// NormalFormContext works like NormalForm, but gives up when the context is
+// done or the limits are exceeded.
+func NormalFormContext(ctx context.Context, poly Poly, basis []Poly, order Order, limits Limits) (Poly, error) {
+	_, rem, err := divMod(poly, basis, order, newGuard(ctx, limits))
+	return rem, err
+}
+
+// criticalPair is a pair of basis indices with the lcm of their leading
+// monomials.
+type criticalPair struct {
+	i, j int
+	lcm  Monomial
+}
+
+func groebner(polys []Poly, order Order, g *guard) ([]Poly, error) {
+	var basis []Poly
+	var leads []Monomial
+
+	for _, poly := range polys {
+		if poly.IsZero() {
+			continue
+		}
+
+		field := poly.Ring()
+		if !field.IsField() {
+			field = Rationals
+		}
+
+		poly, err := poly.To(field)
+		if err != nil {
+			return nil, err
+		}
+
+		basis = append(basis, poly.monicIn(order))
+		leads = append(leads, basis[len(basis)-1].Lead(order).Mono)
+	}
+
+	pairs := make(map[[2]int]criticalPair)
+
+	addPairs := func(j int) {
+		for i := 0; i < j; i++ {
+			pairs[[2]int{i, j}] = criticalPair{i: i, j: j, lcm: leads[i].Lcm(leads[j])}
+		}
+	}
+
+	for j := range basis {
+		addPairs(j)
+	}
+
+	for len(pairs) > 0 {
+		// the normal strategy: the pair with the least lcm first
+		var pair criticalPair
+		first := true
+
+		for _, candidate := range pairs {
+			if first || comparePairs(order, candidate, pair) < 0 {
+				pair, first = candidate, false
+			}
+		}
+
+		delete(pairs, [2]int{pair.i, pair.j})
+
+		if err := g.step(); err != nil {
+			return nil, err
+		}
+
+		if pair.lcm.Degree() == leads[pair.i].Degree()+leads[pair.j].Degree() || chainCriterion(pair, leads, pairs) {
+			continue
+		}
+
+		_, rem, err := divMod(sPoly(basis[pair.i], basis[pair.j], pair.lcm, order), basis, order, g)
+
+		if err != nil {
+			return nil, err
+		}
+
+		if !rem.IsZero() {
+			basis = append(basis, rem.monicIn(order))
+			leads = append(leads, basis[len(basis)-1].Lead(order).Mono)
+			addPairs(len(basis) - 1)
+		}
+	}
+
+	return reduceBasis(basis, leads, order, g)
+}
+
+// comparePairs orders pairs by their lcm, then by indices, so that the
+// choice does not depend on map iteration.
+func comparePairs(order Order, a, b criticalPair) int {
+	if cmp := order.Compare(a.lcm, b.lcm); cmp != 0 {
+		return cmp
+	}
+	switch {
+	case a.j != b.j:
+		return a.j - b.j
+	default:
+		return a.i - b.i
+	}
+}
+
+// chainCriterion reports whether the S-polynomial of the pair is redundant:
+// some other leading monomial divides its lcm and both pairs it forms with
+// the pair's polynomials have already been treated.
+func chainCriterion(pair criticalPair, leads []Monomial, pending map[[2]int]criticalPair) bool {
+	for k, lead := range leads {
+		if k == pair.i || k == pair.j {
+			continue
+		}
+		if _, ok := pair.lcm.Div(lead); !ok {
+			continue
+		}
+		if _, ok := pending[pairKey(pair.i, k)]; ok {
+			continue
+		}
+		if _, ok := pending[pairKey(pair.j, k)]; ok {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+func pairKey(i, j int) [2]int {
+	if i > j {
+		i, j = j, i
+	}
+	return [2]int{i, j}
+}
+
+// sPoly returns the S-polynomial of monic polynomials, the difference of
+// their multiples with the leading terms cancelled.
+func sPoly(a, b Poly, lcm Monomial, order Order) Poly {
+	factorA, _ := lcm.Div(a.Lead(order).Mono)
+	factorB, _ := lcm.Div(b.Lead(order).Mono)
+
+	return a.mulMonomial(factorA).Sub(b.mulMonomial(factorB))
+}
+
+func (poly Poly) mulMonomial(mono Monomial) Poly {
+	result := NewPolyRing(poly.Ring())
+
+	for _, term := range poly.terms {
+		result.addTerm(term.Coeff, term.Mono.Mul(mono))
+	}
+	return result
+}
+
+// monicIn divides a polynomial over a field by its leading coefficient in
+// the order.
+func (poly Poly) monicIn(order Order) Poly {
+	result, _ := poly.divConst(poly.Lead(order).Coeff)
+	return result
+}
+
+// reduceBasis drops the polynomials whose leading monomials are divisible by
+// others and reduces the rest modulo each other.
+func reduceBasis(basis []Poly, leads []Monomial, order Order, g *guard) ([]Poly, error) {
+	var minimal []Poly
+
+	for i, lead := range leads {
+		redundant := false
+
+		for j, other := range leads {
+			if _, ok := lead.Div(other); ok && i != j && (len(lead) != len(other) || compareLex(lead, other) != 0 || j < i) {
+				redundant = true
+				break
+			}
+		}
+
+		if !redundant {
+			minimal = append(minimal, basis[i])
+		}
+	}
+
+	reduced := make([]Poly, len(minimal))
+
+	for i, poly := range minimal {
+		others := make([]Poly, 0, len(minimal)-1)
+		others = append(others, minimal[:i]...)
+		others = append(others, minimal[i+1:]...)
+
+		_, rem, err := divMod(poly, others, order, g)
+
+		if err != nil {
+			return nil, err
+		}
+
+		reduced[i] = rem
+	}
+
+	sort.Slice(reduced, func(i, j int) bool {
+		return order.Compare(reduced[i].Lead(order).Mono, reduced[j].Lead(order).Mono) < 0
+	})
+
+	return reduced, nil
+}
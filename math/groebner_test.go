@@ -0,0 +1,115 @@
+// Copyright (c) 2017 Przemysław Dobrowolski
+//
+// This file is part of the math-mod, a package for symbolic manipulation
+// of large algebraic expressions.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package math
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"context"
+)
+
+func expandAll(infixes ...string) (polys []Poly) {
+	for _, infix := range infixes {
+		polys = append(polys, expandString(infix))
+	}
+	return
+}
+
+func groebnerStrings(polys []Poly, order Order) (result []string) {
+	basis, err := Groebner(polys, order)
+	Expect(err).ShouldNot(HaveOccurred())
+
+	for _, poly := range basis {
+		result = append(result, poly.ExprIn(order).String())
+	}
+	return
+}
+
+var _ = Describe("Groebner Object", func() {
+	Context("when a basis is computed in lex order", func() {
+		It("should eliminate variables", func() {
+			polys := expandAll("x^2 + y^2 + z^2 - 1", "x^2 + z^2 - y", "x - z")
+
+			Expect(groebnerStrings(polys, Lex)).To(Equal([]string{
+				"z ^ 4 + 1 / 2 * z ^ 2 - 1 / 4",
+				"y - 2 * z ^ 2",
+				"x - z",
+			}))
+		})
+	})
+
+	Context("when a basis is computed in graded orders", func() {
+		It("should be reduced", func() {
+			polys := expandAll("x^3 - 2 x y", "x^2 y - 2 y^2 + x")
+
+			Expect(groebnerStrings(polys, Grlex)).To(Equal([]string{
+				"y ^ 2 - 1 / 2 * x",
+				"x * y",
+				"x ^ 2",
+			}))
+			Expect(groebnerStrings(expandAll("x y - 1", "y^2 - 1"), Grevlex)).To(Equal([]string{
+				"x - y",
+				"y ^ 2 - 1",
+			}))
+		})
+
+		It("should work modulo a prime", func() {
+			mod5, err := NewModular(5)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			basis, err := Groebner([]Poly{expandRing("x^2 + 1", mod5), expandRing("x y + 3", mod5)}, Grlex)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(basis).To(HaveLen(2))
+			Expect(basis[0].String()).To(Equal("x + 3 * y"))
+			Expect(basis[1].String()).To(Equal("y ^ 2 + 4"))
+		})
+	})
+
+	Context("when an expression is reduced modulo a basis", func() {
+		It("should give the same normal form for equal classes", func() {
+			basis, err := Groebner(expandAll("sx^2 + cx^2 - 1"), Grevlex)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			a, err := NormalForm(expandString("sx^4 + 2 sx^2 cx^2 + cx^4"), basis, Grevlex)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(a.String()).To(Equal("1"))
+
+			b, err := NormalForm(expandString("(sx^2 + cx^2)^3 + sx"), basis, Grevlex)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(b.String()).To(Equal("sx + 1"))
+		})
+	})
+
+	Context("when the limits are exceeded", func() {
+		It("should fail", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+
+			polys := expandAll("x^5 + y^4 + z^3 - 1", "x^3 + y^3 + z^2 - 1", "x^2 y + z - 3")
+			_, err := GroebnerContext(ctx, polys, Grevlex, Limits{MaxTerms: 5})
+			Expect(err).Should(HaveOccurred())
+		})
+	})
+})
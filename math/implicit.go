@@ -23,6 +23,102 @@
 
 package math
 
+import (
+	"fmt"
+)
+
+// MulPolicy selects between which adjacent tokens a multiplication is
+// implicit. Adjacent numbers, as in "2 3", are never multiplied.
+type MulPolicy int
+
+const (
+	// MulPermissive multiplies adjacent operands, brackets and function
+	// calls, as in "x y", "x(y)" or "(x)2".
+	MulPermissive MulPolicy = iota
+	// MulStrict multiplies only after a number or a closing bracket, as in
+	// "2x", "2 sin(x)", "(x)y" or "(x)(y)", leaving "x y" or "x(y)" an error.
+	MulStrict
+	// MulOff requires every multiplication to be written.
+	MulOff
+)
+
+var mulPolicyNames = map[MulPolicy]string{
+	MulPermissive: "permissive",
+	MulStrict:     "strict",
+	MulOff:        "off",
+}
+
+func ParseMulPolicy(name string) (MulPolicy, error) {
+	for policy, policyName := range mulPolicyNames {
+		if name == policyName {
+			return policy, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown implicit multiplication policy: %v", name)
+}
+
+func (policy MulPolicy) String() string {
+	return mulPolicyNames[policy]
+}
+
+// ImplicitMul records a multiplication inserted between two tokens, at the
+// position of the right one.
+type ImplicitMul struct {
+	Pos         int
+	Left, Right Token
+}
+
+func (mul ImplicitMul) String() string {
+	return fmt.Sprintf("position %d: implicit multiplication: %v * %v", mul.Pos, mul.Left, mul.Right)
+}
+
+// insertMul inserts the multiplications allowed by the policy between
+// adjacent tokens and fails on adjacent tokens which it does not join.
+func insertMul(infix Tokens, positions []int, policy MulPolicy) (Tokens, []int, []ImplicitMul, error) {
+	var result Tokens
+	var resultPositions []int
+	var muls []ImplicitMul
+
+	for i, token := range infix {
+		if i > 0 && adjacent(infix[i-1], token) {
+			prev := infix[i-1]
+			pos := positions[i]
+
+			switch {
+			case prev.Kind == KindInt && token.Kind == KindInt:
+				return nil, nil, nil, &ParseError{Pos: pos, Msg: fmt.Sprintf("ambiguous adjacent numbers: %v %v", prev, token)}
+			case policy == MulOff:
+				return nil, nil, nil, &ParseError{Pos: pos, Msg: fmt.Sprintf("missing operator between %v and %v", prev, token)}
+			case policy == MulStrict && (prev.Kind == KindVar || token.Kind == KindInt):
+				return nil, nil, nil, &ParseError{Pos: pos, Msg: fmt.Sprintf("ambiguous implicit multiplication of %v and %v", prev, token)}
+			}
+
+			result = append(result, NewMul())
+			resultPositions = append(resultPositions, pos)
+			muls = append(muls, ImplicitMul{Pos: pos, Left: prev, Right: token})
+		}
+
+		result = append(result, token)
+		resultPositions = append(resultPositions, positions[i])
+	}
+
+	return result, resultPositions, muls, nil
+}
+
+// adjacent reports whether the tokens may only be joined by multiplication.
+func adjacent(prev, token Token) bool {
+	switch prev.Kind {
+	case KindInt, KindVar, KindClose:
+		switch token.Kind {
+		case KindInt, KindVar, KindOpen, KindFunc:
+			return true
+		}
+	}
+	return false
+}
+
+// ImplicitOperMul inserts a multiplication between all adjacent operands,
+// like the permissive policy but accepting adjacent numbers.
 func ImplicitOperMul(tokens Tokens) (result Tokens) {
 	result = Tokens{}
 
@@ -32,7 +128,7 @@ func ImplicitOperMul(tokens Tokens) (result Tokens) {
 			switch prev.Kind {
 			case KindInt, KindVar, KindClose:
 				switch token.Kind {
-				case KindInt, KindVar, KindOpen:
+				case KindInt, KindVar, KindOpen, KindFunc:
 					result = append(result, Token{Kind: KindMul})
 				}
 			}
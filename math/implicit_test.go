@@ -0,0 +1,104 @@
+// Copyright (c) 2017 Przemysław Dobrowolski
+//
+// This file is part of the math-mod, a package for symbolic manipulation
+// of large algebraic expressions.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package math
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Implicit Object", func() {
+	parse := func(policy MulPolicy, src string) (string, []ImplicitMul, error) {
+		var muls []ImplicitMul
+		parser := &Parser{Mul: policy, Explain: func(mul ImplicitMul) { muls = append(muls, mul) }}
+		expr, err := parser.ParseString(src)
+
+		if err != nil {
+			return "", muls, err
+		}
+		return expr.String(), muls, nil
+	}
+
+	Context("when multiplication is permissive", func() {
+		It("should multiply adjacent operands", func() {
+			expr, muls, err := parse(MulPermissive, "2x3 + x y + x(y) + (x)2")
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(expr).To(Equal("2 * x3 + x * y + x * y + x * 2"))
+			Expect(muls).To(Equal([]ImplicitMul{
+				{Pos: 1, Left: NewInt(2), Right: NewVar("x3")},
+				{Pos: 8, Left: NewVar("x"), Right: NewVar("y")},
+				{Pos: 13, Left: NewVar("x"), Right: NewOpen()},
+				{Pos: 22, Left: NewClose(), Right: NewInt(2)},
+			}))
+			Expect(muls[0].String()).To(Equal("position 1: implicit multiplication: 2 * x3"))
+		})
+
+		It("should reject adjacent numbers", func() {
+			_, muls, err := parse(MulPermissive, "x y + 2 3")
+			Expect(err).To(Equal(&ParseError{Pos: 8, Msg: "ambiguous adjacent numbers: 2 3"}))
+			Expect(muls).To(BeEmpty())
+		})
+	})
+
+	Context("when multiplication is strict", func() {
+		It("should multiply after numbers and brackets only", func() {
+			expr, _, err := parse(MulStrict, "2x + 2 sin(x) + (x)y + (x)(y)")
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(expr).To(Equal("2 * x + 2 * sin ( x ) + x * y + x * y"))
+
+			_, _, err = parse(MulStrict, "x y")
+			Expect(err).To(Equal(&ParseError{Pos: 2, Msg: "ambiguous implicit multiplication of x and y"}))
+
+			_, _, err = parse(MulStrict, "f(x)")
+			Expect(err).To(Equal(&ParseError{Pos: 1, Msg: "ambiguous implicit multiplication of f and ("}))
+
+			_, _, err = parse(MulStrict, "(x) 2")
+			Expect(err).To(Equal(&ParseError{Pos: 4, Msg: "ambiguous implicit multiplication of ) and 2"}))
+		})
+	})
+
+	Context("when multiplication is off", func() {
+		It("should require operators", func() {
+			expr, muls, err := parse(MulOff, "2 * x")
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(expr).To(Equal("2 * x"))
+			Expect(muls).To(BeEmpty())
+
+			_, _, err = parse(MulOff, "2x")
+			Expect(err).To(Equal(&ParseError{Pos: 1, Msg: "missing operator between 2 and x"}))
+		})
+	})
+
+	Context("when a policy is named", func() {
+		It("should parse it", func() {
+			policy, err := ParseMulPolicy("strict")
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(policy).To(Equal(MulStrict))
+			Expect(MulOff.String()).To(Equal("off"))
+
+			_, err = ParseMulPolicy("lenient")
+			Expect(err).Should(HaveOccurred())
+		})
+	})
+})
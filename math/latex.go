@@ -0,0 +1,211 @@
+// Copyright (c) 2017 Przemysław Dobrowolski
+//
+// This file is part of the math-mod, a package for symbolic manipulation
+// of large algebraic expressions.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package math
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// latexGreek maps Greek letters to their LaTeX commands; capitals which look
+// like Latin letters have none.
+var latexGreek = map[rune]string{
+	'α': `\alpha`, 'β': `\beta`, 'γ': `\gamma`, 'δ': `\delta`, 'ε': `\epsilon`,
+	'ζ': `\zeta`, 'η': `\eta`, 'θ': `\theta`, 'ι': `\iota`, 'κ': `\kappa`,
+	'λ': `\lambda`, 'μ': `\mu`, 'ν': `\nu`, 'ξ': `\xi`, 'π': `\pi`,
+	'ρ': `\rho`, 'σ': `\sigma`, 'τ': `\tau`, 'υ': `\upsilon`, 'φ': `\phi`,
+	'χ': `\chi`, 'ψ': `\psi`, 'ω': `\omega`,
+	'Γ': `\Gamma`, 'Δ': `\Delta`, 'Θ': `\Theta`, 'Λ': `\Lambda`, 'Ξ': `\Xi`,
+	'Π': `\Pi`, 'Σ': `\Sigma`, 'Υ': `\Upsilon`, 'Φ': `\Phi`, 'Ψ': `\Psi`,
+	'Ω': `\Omega`,
+}
+
+// latexFunctions maps functions to LaTeX operators other than \operatorname.
+var latexFunctions = map[string]string{
+	"sin": `\sin`,
+	"cos": `\cos`,
+	"tan": `\tan`,
+	"exp": `\exp`,
+	"log": `\log`,
+}
+
+// Latex renders the expression for LaTeX math mode. Divisions become
+// fractions, and underscores, trailing digits and indices of variables
+// become subscripts, so x_1, x1 and x[1] all render as x_{1}.
+func (expr *Expr) Latex() string {
+	var builder strings.Builder
+	expr.writeLatex(&builder)
+	return builder.String()
+}
+
+func (expr *Expr) writeLatex(builder *strings.Builder) {
+	switch expr.Token.Kind {
+	case KindInt:
+		builder.WriteString(expr.Token.String())
+		return
+	case KindVar:
+		builder.WriteString(latexName(expr.Token.Value.(string)))
+		return
+	case KindFunc:
+		name := expr.Token.Value.(string)
+
+		if name == "sqrt" {
+			builder.WriteString(`\sqrt{`)
+			expr.Args[0].writeLatex(builder)
+			builder.WriteString("}")
+			return
+		}
+
+		if op, ok := latexFunctions[name]; ok {
+			builder.WriteString(op)
+		} else {
+			builder.WriteString(`\operatorname{` + name + "}")
+		}
+		expr.Args[0].writeLatexOperand(builder, true)
+		return
+	}
+
+	left, right := expr.Args[0], expr.Args[1]
+
+	if expr.Token.Kind == KindDiv {
+		builder.WriteString(`\frac{`)
+		left.writeLatex(builder)
+		builder.WriteString("}{")
+		right.writeLatex(builder)
+		builder.WriteString("}")
+		return
+	}
+
+	prop := OperProps[expr.Token.Kind]
+	left.writeLatexOperand(builder, left.prec() < prop.prec || left.prec() == prop.prec && prop.rightAssoc)
+
+	switch expr.Token.Kind {
+	case KindPow:
+		builder.WriteString("^{")
+		right.writeLatex(builder)
+		builder.WriteString("}")
+		return
+	case KindMul:
+		builder.WriteString(` \cdot `)
+	default:
+		builder.WriteString(" " + expr.Token.String() + " ")
+	}
+
+	right.writeLatexOperand(builder, right.prec() < prop.prec || right.prec() == prop.prec && !prop.rightAssoc)
+}
+
+func (expr *Expr) writeLatexOperand(builder *strings.Builder, bracket bool) {
+	if !bracket {
+		expr.writeLatex(builder)
+		return
+	}
+
+	builder.WriteString(`\left(`)
+	expr.writeLatex(builder)
+	builder.WriteString(`\right)`)
+}
+
+// latexName renders a variable name with its subscripts.
+func latexName(name string) string {
+	var subscripts []string
+
+	if open := strings.IndexByte(name, '['); open >= 0 {
+		for _, index := range strings.Split(name[open+1:len(name)-1], ",") {
+			if isInteger([]byte(strings.TrimPrefix(index, "-"))) {
+				subscripts = append(subscripts, index)
+			} else {
+				subscripts = append(subscripts, latexName(index))
+			}
+		}
+		name = name[:open]
+	}
+
+	// a trailing underscore is kept in the name
+	if underscore := strings.IndexByte(name, '_'); underscore > 0 && underscore < len(name)-1 {
+		subscripts = append([]string{latexName(name[underscore+1:])}, subscripts...)
+		name = name[:underscore]
+	}
+
+	// trailing digits are a subscript unless the name is a number
+	digits := len(name)
+	for digits > 0 {
+		r, size := utf8.DecodeLastRuneInString(name[:digits])
+		if !(r >= '0' && r <= '9' || r >= '₀' && r <= '₉') {
+			break
+		}
+		digits -= size
+	}
+
+	if digits > 0 && digits < len(name) {
+		subscripts = append([]string{latexDigits(name[digits:])}, subscripts...)
+		name = name[:digits]
+	} else if digits == 0 {
+		name = latexDigits(name)
+	}
+
+	var builder strings.Builder
+
+	if utf8.RuneCountInString(name) == 1 || digits == 0 {
+		builder.WriteString(latexRunes(name))
+	} else {
+		builder.WriteString(`\mathit{` + latexRunes(name) + "}")
+	}
+
+	if len(subscripts) > 0 {
+		builder.WriteString("_{" + strings.Join(subscripts, ",") + "}")
+	}
+	return builder.String()
+}
+
+// latexRunes writes Greek letters as commands and escapes underscores.
+func latexRunes(name string) string {
+	var builder strings.Builder
+
+	for i, r := range name {
+		switch greek, ok := latexGreek[r]; {
+		case ok:
+			builder.WriteString(greek)
+			// keep the command apart from a following letter
+			if next, _ := utf8.DecodeRuneInString(name[i+utf8.RuneLen(r):]); unicode.IsLetter(next) && latexGreek[next] == "" {
+				builder.WriteByte(' ')
+			}
+		case r == '_':
+			builder.WriteString(`\_`)
+		default:
+			builder.WriteRune(r)
+		}
+	}
+	return builder.String()
+}
+
+// latexDigits replaces subscript digits by decimal ones.
+func latexDigits(digits string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= '₀' && r <= '₉' {
+			return '0' + r - '₀'
+		}
+		return r
+	}, digits)
+}
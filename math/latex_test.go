@@ -0,0 +1,47 @@
+// Copyright (c) 2017 Przemysław Dobrowolski
+//
+// This file is part of the math-mod, a package for symbolic manipulation
+// of large algebraic expressions.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package math
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Latex Object", func() {
+	Context("when an expression is typeset", func() {
+		It("should use fractions and brackets", func() {
+			Expect(parseTree("(x + 1) * y / (2 - z) - x^(a + b)").Latex()).To(Equal(`\frac{\left(x + 1\right) \cdot y}{2 - z} - x^{a + b}`))
+			Expect(parseTree("(x / y)^2 + sin(t) + sqrt(t) + log(t)").Latex()).To(Equal(`\left(\frac{x}{y}\right)^{2} + \sin\left(t\right) + \sqrt{t} + \log\left(t\right)`))
+			Expect(parseTree("a - (b - c)").Latex()).To(Equal(`a - \left(b - c\right)`))
+		})
+
+		It("should subscript variables", func() {
+			Expect(parseTree("x_1 + x1 + x₁ + x[1]").Latex()).To(Equal(`x_{1} + x_{1} + x_{1} + x_{1}`))
+			Expect(parseTree("θ2 * α_i * a[i, -1] * speed").Latex()).To(Equal(`\theta_{2} \cdot \alpha_{i} \cdot a_{i,-1} \cdot \mathit{speed}`))
+			Expect(parseTree("x_i_j + Ω[α]").Latex()).To(Equal(`x_{i_{j}} + \Omega_{\alpha}`))
+			Expect(parseTree("x_ + a_b_").Latex()).To(Equal(`\mathit{x\_} + a_{\mathit{b\_}}`))
+			Expect(parseTree("αβ + αx_1 + xα").Latex()).To(Equal(`\mathit{\alpha\beta} + \mathit{\alpha x}_{1} + \mathit{x\alpha}`))
+		})
+	})
+})
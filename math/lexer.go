@@ -25,56 +25,139 @@ package math
 
 import (
 	"bufio"
+	"bytes"
+	"fmt"
 	"io"
-	"strconv"
+	"math/big"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 )
 
 func ParseInfix(reader io.Reader) (Tokens, error) {
+	tokens, _, err := ParseInfixPositions(reader)
+	return tokens, err
+}
+
+// ParseInfixPositions works like ParseInfix and additionally returns the
+// byte offset of every token in the input.
+func ParseInfixPositions(reader io.Reader) (Tokens, []int, error) {
 	tokens := Tokens{}
+	positions := []int{}
 	scanner := bufio.NewScanner(reader)
 
+	// offset of data passed to the split function and of the last token
+	offset, start := 0, 0
+	inComment := false
+
 	scanner.Split(func(data []byte, atEOF bool) (advance int, token []byte, err error) {
 		i := 0
 
-		// omit whitespace
+		// a comment may span many reads of long lines
+		if inComment {
+			i = skipComment(data, 0)
+			inComment = i == len(data) && !atEOF
+		}
+
+		// omit whitespace and comments, which run from '#' or '//' to the end of line
 	omit_whitespace:
 		for i < len(data) {
 			switch data[i] {
 			case '\t', '\n', '\v', '\f', '\r', ' ':
 				i++
 				continue
+			case '/':
+				if i+1 == len(data) && !atEOF {
+					offset += i
+					return i, nil, nil
+				}
+				if i+1 == len(data) || data[i+1] != '/' {
+					break omit_whitespace
+				}
+			case '#':
 			default:
 				break omit_whitespace
 			}
+
+			i = skipComment(data, i)
+			if i == len(data) && !atEOF {
+				inComment = true
+			}
 		}
 
 		if i == len(data) {
+			offset += i
 			return i, nil, nil
 		}
 
 		// scan one-character tokens
 		switch data[i] {
-		case '+', '-', '*', '^', '(', ')':
-			return i + 1, data[i: i+1], nil
+		case '+', '-', '*', '/', '^', '(', ')':
+			start, offset = offset+i, offset+i+1
+			return i + 1, data[i : i+1], nil
 		}
 
-		// scan integer or variable
+		// scan integer or variable, which may be indexed as in a[i,1]; names
+		// do not start with a digit, so 2x3 reads as 2 followed by x3
 		j := i
 
-		for j < len(data) {
-			if (data[j] >= 'a' && data[j] <= 'z') || (data[j] >= 'A' && data[j] <= 'Z') || (data[j] >= '0' && data[j] <= '9') {
+		if data[i] >= '0' && data[i] <= '9' {
+			for j < len(data) && data[j] >= '0' && data[j] <= '9' {
 				j++
-			} else {
-				break
 			}
+		} else {
+			for j < len(data) && utf8.FullRune(data[j:]) {
+				r, size := utf8.DecodeRune(data[j:])
+				if !isNameRune(r) {
+					break
+				}
+				j += size
+			}
+		}
+
+		if j == i {
+			if !utf8.FullRune(data[i:]) && !atEOF {
+				offset += i
+				return i, nil, nil
+			}
+			r, _ := utf8.DecodeRune(data[i:])
+			return 0, nil, &ParseError{Pos: offset + i, Msg: fmt.Sprintf("unexpected character: %q", r)}
+		}
+
+		// the token may continue in data not read yet
+		if !atEOF && (j == len(data) || !utf8.FullRune(data[j:])) {
+			offset += i
+			return i, nil, nil
+		}
+
+		if j < len(data) && data[j] == '[' && !isInteger(data[i:j]) {
+			end := bytes.IndexByte(data[j:], ']')
+
+			if end < 0 {
+				if !atEOF {
+					offset += i
+					return i, nil, nil
+				}
+				return 0, nil, &ParseError{Pos: offset + j, Msg: "unclosed index"}
+			}
+
+			for _, index := range bytes.Split(data[j+1:j+end], []byte(",")) {
+				if index = bytes.TrimSpace(index); !isIndex(index) {
+					return 0, nil, &ParseError{Pos: offset + j, Msg: fmt.Sprintf("invalid index: %q", index)}
+				}
+			}
+
+			j += end + 1
 		}
 
+		start, offset = offset+i, offset+j
 		return j, data[i:j], nil
 	})
 
 	for scanner.Scan() {
 		raw := scanner.Text()
+		positions = append(positions, start)
+
 		switch raw {
 		case "+":
 			tokens = append(tokens, NewPlus())
@@ -88,6 +171,10 @@ func ParseInfix(reader io.Reader) (Tokens, error) {
 			tokens = append(tokens, NewMul())
 			continue
 
+		case "/":
+			tokens = append(tokens, NewDiv())
+			continue
+
 		case "^":
 			tokens = append(tokens, NewPow())
 			continue
@@ -101,17 +188,68 @@ func ParseInfix(reader io.Reader) (Tokens, error) {
 			continue
 		}
 
-		// integer or variable
-		i, err := strconv.ParseInt(raw, 10, 64)
+		// integer or variable, with whitespace dropped from indices
+		if strings.Contains(raw, "[") {
+			raw = strings.Join(strings.Fields(raw), "")
+		}
+
+		i, ok := new(big.Int).SetString(raw, 10)
 
-		if err != nil {
-			tokens = append(tokens, NewVar(raw))
+		if !ok {
+			if Functions[raw] {
+				tokens = append(tokens, NewFunc(raw))
+			} else {
+				tokens = append(tokens, NewVar(raw))
+			}
 		} else {
-			tokens = append(tokens, NewInt(i))
+			tokens = append(tokens, NewBigInt(i))
 		}
 	}
 
-	return tokens, nil
+	if err := scanner.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	return tokens, positions, nil
+}
+
+// isNameRune reports whether the rune may occur in an integer or variable
+// name: letters, decimal and subscript digits and underscores.
+func isNameRune(r rune) bool {
+	return unicode.IsLetter(r) || r >= '0' && r <= '9' || r >= '₀' && r <= '₉' || r == '_'
+}
+
+func isInteger(data []byte) bool {
+	for _, c := range data {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return len(data) > 0
+}
+
+// isIndex reports whether the data is an index of a variable: an integer,
+// possibly negative, or a name.
+func isIndex(data []byte) bool {
+	if isInteger(bytes.TrimPrefix(data, []byte("-"))) {
+		return true
+	}
+
+	for _, r := range string(data) {
+		if !isNameRune(r) {
+			return false
+		}
+	}
+	return len(data) > 0
+}
+
+// skipComment returns the index of the end of line closing the comment
+// starting at i, or the length of data if the line does not end in it.
+func skipComment(data []byte, i int) int {
+	if end := bytes.IndexByte(data[i:], '\n'); end >= 0 {
+		return i + end
+	}
+	return len(data)
 }
 
 func ParseInfixString(infix string) (Tokens, error) {
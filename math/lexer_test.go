@@ -24,17 +24,12 @@
 package math
 
 import (
+	"strings"
+
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
-
-	"testing"
 )
 
-func TestLexer(t *testing.T) {
-	RegisterFailHandler(Fail)
-	RunSpecs(t, "Lexer Suite")
-}
-
 var _ = Describe("Lexer Object", func() {
 	var (
 		infix Tokens
@@ -90,4 +85,53 @@ var _ = Describe("Lexer Object", func() {
 			Expect(infix).To(Equal(Tokens{NewOpen()}))
 		})
 	})
+
+	Context("when comments are parsed", func() {
+		It("should skip them", func() {
+			var positions []int
+			infix, positions, err = ParseInfixPositions(strings.NewReader("x # one\n/ // two\ny //"))
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(infix).To(Equal(Tokens{NewVar("x"), NewDiv(), NewVar("y")}))
+			Expect(positions).To(Equal([]int{0, 8, 17}))
+		})
+
+		It("should skip comments longer than the buffer", func() {
+			infix, err = ParseInfixString("x # " + strings.Repeat("*", 100000) + "\n+ 1")
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(infix).To(Equal(Tokens{NewVar("x"), NewPlus(), NewInt(1)}))
+		})
+	})
+
+	Context("when names are not plain ASCII", func() {
+		It("should accept Unicode letters and underscores", func() {
+			infix, err = ParseInfixString("α + θ2 * x_1 - x₁ + _tmp")
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(infix).To(Equal(Tokens{NewVar("α"), NewPlus(), NewVar("θ2"), NewMul(), NewVar("x_1"), NewMinus(), NewVar("x₁"), NewPlus(), NewVar("_tmp")}))
+		})
+
+		It("should accept indexed variables", func() {
+			var positions []int
+			infix, positions, err = ParseInfixPositions(strings.NewReader("a[1, 2] b[i,-1]^2"))
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(infix).To(Equal(Tokens{NewVar("a[1,2]"), NewVar("b[i,-1]"), NewPow(), NewInt(2)}))
+			Expect(positions).To(Equal([]int{0, 8, 15, 16}))
+		})
+
+		It("should reject invalid indices", func() {
+			_, err = ParseInfixString("a[1,]")
+			Expect(err).To(Equal(&ParseError{Pos: 1, Msg: `invalid index: ""`}))
+
+			_, err = ParseInfixString("x + a[1")
+			Expect(err).To(Equal(&ParseError{Pos: 5, Msg: "unclosed index"}))
+
+			_, err = ParseInfixString("2[1]")
+			Expect(err).To(Equal(&ParseError{Pos: 1, Msg: "unexpected character: '['"}))
+		})
+
+		It("should read names split between buffers", func() {
+			infix, err = ParseInfixString(strings.Repeat(" ", 4095) + "αβ[1, 2]")
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(infix).To(Equal(Tokens{NewVar("αβ[1,2]")}))
+		})
+	})
 })
@@ -0,0 +1,142 @@
+// Copyright (c) 2017 Przemysław Dobrowolski
+//
+// This file is part of the math-mod, a package for symbolic manipulation
+// of large algebraic expressions.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package math
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+)
+
+// Limits bounds the resources a transformation may use. A zero field leaves
+// the resource unlimited.
+type Limits struct {
+	MaxTerms  int   // terms of any intermediate polynomial
+	MaxMemory int64 // estimated bytes of any intermediate polynomial
+	MaxDepth  int   // depth of the expression tree
+}
+
+// LimitError reports that a transformation exceeded one of its Limits.
+type LimitError struct {
+	Resource string
+	Limit    int64
+}
+
+func (err *LimitError) Error() string {
+	return fmt.Sprintf("%s limit exceeded: %d", err.Resource, err.Limit)
+}
+
+// checkInterval is the number of loop iterations between checks of the
+// context, which are too expensive to do on every iteration.
+const checkInterval = 1024
+
+// guard enforces a context and limits on a running transformation. A nil
+// guard enforces nothing.
+type guard struct {
+	ctx      context.Context
+	limits   Limits
+	steps    int
+	termSize int64 // largest estimated term size seen
+	jobs     int   // goroutines to multiply with, see ExpandParallel
+}
+
+func newGuard(ctx context.Context, limits Limits) *guard {
+	return &guard{
+		ctx:    ctx,
+		limits: limits,
+	}
+}
+
+func (g *guard) step() error {
+	if g == nil {
+		return nil
+	}
+
+	g.steps++
+
+	if g.steps%checkInterval == 0 {
+		return g.ctx.Err()
+	}
+
+	return nil
+}
+
+// check checks the context right away, for loops whose iterations are
+// expensive enough to outweigh it.
+func (g *guard) check() error {
+	if g == nil {
+		return nil
+	}
+
+	return g.ctx.Err()
+}
+
+// checkTerms verifies the number of terms of a polynomial that has just
+// received the term coeff*mono against the limits. Its memory is estimated
+// from the largest term seen so far.
+func (g *guard) checkTerms(terms int, coeff *big.Rat, mono Monomial) error {
+	if g == nil {
+		return nil
+	}
+
+	if g.limits.MaxTerms > 0 && terms > g.limits.MaxTerms {
+		return &LimitError{Resource: "terms", Limit: int64(g.limits.MaxTerms)}
+	}
+
+	if g.limits.MaxMemory > 0 {
+		if size := termSize(coeff, mono); size > g.termSize {
+			g.termSize = size
+		}
+
+		if int64(terms)*g.termSize > g.limits.MaxMemory {
+			return &LimitError{Resource: "memory", Limit: g.limits.MaxMemory}
+		}
+	}
+
+	return nil
+}
+
+// termSize estimates the bytes taken by a term stored in a Poly, including
+// its map key and bookkeeping.
+func termSize(coeff *big.Rat, mono Monomial) int64 {
+	size := int64(96 + 8*len(coeff.Num().Bits()) + 8*len(coeff.Denom().Bits()))
+
+	for _, power := range mono {
+		size += int64(32 + 2*len(power.Var))
+	}
+
+	return size
+}
+
+func (g *guard) checkDepth(depth int) error {
+	if g == nil {
+		return nil
+	}
+
+	if g.limits.MaxDepth > 0 && depth > g.limits.MaxDepth {
+		return &LimitError{Resource: "depth", Limit: int64(g.limits.MaxDepth)}
+	}
+
+	return nil
+}
@@ -0,0 +1,78 @@
+// Copyright (c) 2017 Przemysław Dobrowolski
+//
+// This file is part of the math-mod, a package for symbolic manipulation
+// of large algebraic expressions.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package math
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"context"
+)
+
+var _ = Describe("Limits Object", func() {
+	Context("when expansion stays within limits", func() {
+		It("should succeed", func() {
+			poly, err := ExpandContext(context.Background(), parseTree("(x + y + z)^3"), Limits{MaxTerms: 10, MaxDepth: 4})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(poly.Len()).To(Equal(10))
+		})
+	})
+
+	Context("when expansion exceeds the term limit", func() {
+		It("should fail with a limit error", func() {
+			_, err := ExpandContext(context.Background(), parseTree("(x + y + z)^4"), Limits{MaxTerms: 10})
+			Expect(err).To(Equal(&LimitError{Resource: "terms", Limit: 10}))
+		})
+	})
+
+	Context("when expansion exceeds the memory limit", func() {
+		It("should fail with a limit error", func() {
+			_, err := ExpandContext(context.Background(), parseTree("(1 + x)^1000"), Limits{MaxMemory: 1 << 16})
+			Expect(err).To(Equal(&LimitError{Resource: "memory", Limit: 1 << 16}))
+		})
+	})
+
+	Context("when the tree is too deep", func() {
+		It("should fail with a limit error", func() {
+			_, err := ExpandContext(context.Background(), parseTree("x + y + z"), Limits{MaxDepth: 2})
+			Expect(err).To(Equal(&LimitError{Resource: "depth", Limit: 2}))
+		})
+	})
+
+	Context("when the context is cancelled", func() {
+		It("should stop", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+
+			_, err := ExpandContext(ctx, parseTree("(a + b + c + d + e + f)^12"), Limits{})
+			Expect(err).To(Equal(context.Canceled))
+
+			rule, err := ParseRule("?x -> ?x + 0")
+			Expect(err).ShouldNot(HaveOccurred())
+
+			_, _, err = RewriteContext(ctx, parseTree("y"), []Rule{rule}, 100, Limits{})
+			Expect(err).To(Equal(context.Canceled))
+		})
+	})
+})
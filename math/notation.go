@@ -23,4 +23,18 @@
 
 package math
 
+import "strings"
+
 type Tokens []Token
+
+// Join returns the tokens separated by spaces, which is how expressions in
+// postfix and prefix notation are written.
+func (tokens Tokens) Join() string {
+	parts := make([]string, len(tokens))
+
+	for i, token := range tokens {
+		parts[i] = token.String()
+	}
+
+	return strings.Join(parts, " ")
+}
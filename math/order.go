@@ -0,0 +1,360 @@
+// Copyright (c) 2017 Przemysław Dobrowolski
+//
+// This file is part of the math-mod, a package for symbolic manipulation
+// of large algebraic expressions.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package math
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+)
+
+// Order is a monomial order: a total order of monomials compatible with
+// their product. Variables rank alphabetically, the first being the largest.
+type Order interface {
+	// Compare returns 1, 0 or -1 as a is greater than, equal to or less than
+	// b.
+	Compare(a, b Monomial) int
+
+	String() string
+}
+
+// Lex compares exponents variable by variable.
+var Lex Order = lex{}
+
+// Grlex compares total degrees, then exponents like Lex.
+var Grlex Order = grlex{}
+
+// Grevlex compares total degrees; of equal degrees the monomial with the
+// lower exponent of the last variable in which they differ is greater.
+var Grevlex Order = grevlex{}
+
+var orders = map[string]Order{
+	"lex":       Lex,
+	"grlex":     Grlex,
+	"deglex":    Grlex,
+	"grevlex":   Grevlex,
+	"degrevlex": Grevlex,
+}
+
+// ParseOrder returns the monomial order of the given name; deglex and
+// degrevlex are accepted as other names of grlex and grevlex.
+func ParseOrder(name string) (Order, error) {
+	if order, ok := orders[name]; ok {
+		return order, nil
+	}
+	return nil, fmt.Errorf("unknown monomial order: %v", name)
+}
+
+type lex struct{}
+
+func (lex) Compare(a, b Monomial) int {
+	return compareLex(a, b)
+}
+
+func (lex) String() string {
+	return "lex"
+}
+
+type grlex struct{}
+
+func (grlex) Compare(a, b Monomial) int {
+	return compareGrlex(a, b)
+}
+
+func (grlex) String() string {
+	return "grlex"
+}
+
+type grevlex struct{}
+
+func (grevlex) Compare(a, b Monomial) int {
+	if da, db := a.Degree(), b.Degree(); da != db {
+		if da > db {
+			return 1
+		}
+		return -1
+	}
+
+	i, j := len(a)-1, len(b)-1
+
+	for i >= 0 || j >= 0 {
+		switch {
+		case j < 0 || i >= 0 && a[i].Var > b[j].Var:
+			return -1
+		case i < 0 || a[i].Var < b[j].Var:
+			return 1
+		case a[i].Exp < b[j].Exp:
+			return 1
+		case a[i].Exp > b[j].Exp:
+			return -1
+		}
+		i--
+		j--
+	}
+	return 0
+}
+
+func (grevlex) String() string {
+	return "grevlex"
+}
+
+// WithVars returns the order with the variables ranked as listed, the first
+// being the largest. Variables not listed rank below them alphabetically.
+func WithVars(order Order, vars []string) (Order, error) {
+	if r, ok := order.(ranked); ok {
+		order = r.base
+	}
+
+	if order != Lex && order != Grlex && order != Grevlex {
+		return nil, fmt.Errorf("order does not rank variables: %v", order)
+	}
+
+	rank := make(map[string]int)
+
+	for i, name := range vars {
+		if _, ok := rank[name]; ok {
+			return nil, fmt.Errorf("duplicate variable: %v", name)
+		}
+		rank[name] = i
+	}
+
+	return ranked{base: order, vars: vars, rank: rank}, nil
+}
+
+// ranked is a built-in order with a custom ranking of variables.
+type ranked struct {
+	base Order
+	vars []string
+	rank map[string]int
+}
+
+// exponent is the exponent of a variable in two compared monomials.
+type exponent struct {
+	name string
+	a, b int
+}
+
+func (order ranked) Compare(a, b Monomial) int {
+	if order.base != Lex {
+		if da, db := a.Degree(), b.Degree(); da != db {
+			if da > db {
+				return 1
+			}
+			return -1
+		}
+	}
+
+	var exps []exponent
+
+	for i, j := 0, 0; i < len(a) || j < len(b); {
+		switch {
+		case j == len(b) || i < len(a) && a[i].Var < b[j].Var:
+			exps = append(exps, exponent{name: a[i].Var, a: a[i].Exp})
+			i++
+		case i == len(a) || a[i].Var > b[j].Var:
+			exps = append(exps, exponent{name: b[j].Var, b: b[j].Exp})
+			j++
+		default:
+			exps = append(exps, exponent{name: a[i].Var, a: a[i].Exp, b: b[j].Exp})
+			i++
+			j++
+		}
+	}
+
+	sort.Slice(exps, func(i, j int) bool {
+		return order.before(exps[i].name, exps[j].name)
+	})
+
+	if order.base == Grevlex {
+		for i := len(exps) - 1; i >= 0; i-- {
+			switch {
+			case exps[i].a < exps[i].b:
+				return 1
+			case exps[i].a > exps[i].b:
+				return -1
+			}
+		}
+		return 0
+	}
+
+	for _, exp := range exps {
+		switch {
+		case exp.a > exp.b:
+			return 1
+		case exp.a < exp.b:
+			return -1
+		}
+	}
+	return 0
+}
+
+// before reports whether the first variable ranks above the second.
+func (order ranked) before(a, b string) bool {
+	rankA, okA := order.rank[a]
+	rankB, okB := order.rank[b]
+
+	switch {
+	case okA && okB:
+		return rankA < rankB
+	case okA || okB:
+		return okA
+	}
+	return a < b
+}
+
+func (order ranked) String() string {
+	return fmt.Sprintf("%v(%v)", order.base, strings.Join(order.vars, ","))
+}
+
+// Lead returns the leading term in the order; the polynomial must not be
+// zero.
+func (poly Poly) Lead(order Order) (lead Term) {
+	for _, term := range poly.terms {
+		if lead.Coeff == nil || order.Compare(term.Mono, lead.Mono) > 0 {
+			lead = term
+		}
+	}
+	return
+}
+
+// SortTerms returns a copy of the tree in which the terms of every sum are
+// sorted: monomials with integer coefficients in descending order, the same
+// monomials keeping their relative order, followed by the other terms
+// sorted by their infix form.
+func (expr *Expr) SortTerms(order Order) *Expr {
+	if expr.Token.Kind != KindPlus && expr.Token.Kind != KindMinus {
+		args := make([]*Expr, len(expr.Args))
+
+		for i, arg := range expr.Args {
+			args[i] = arg.SortTerms(order)
+		}
+		return NewNode(expr.Token, args...)
+	}
+
+	var terms []signedTerm
+	terms = expr.appendTerms(terms, false)
+
+	for i := range terms {
+		terms[i].expr = terms[i].expr.SortTerms(order)
+		terms[i].mono, terms[i].isMono = terms[i].expr.monomial()
+		terms[i].key = terms[i].expr.String()
+	}
+
+	sort.SliceStable(terms, func(i, j int) bool {
+		a, b := terms[i], terms[j]
+		switch {
+		case a.isMono && b.isMono:
+			return order.Compare(a.mono, b.mono) > 0
+		case a.isMono || b.isMono:
+			return a.isMono
+		}
+		return a.key < b.key
+	})
+
+	result := terms[0].expr
+
+	if terms[0].negative {
+		result = result.negate()
+	}
+
+	for _, term := range terms[1:] {
+		if term.negative {
+			result = NewNode(NewMinus(), result, term.expr)
+		} else {
+			result = NewNode(NewPlus(), result, term.expr)
+		}
+	}
+	return result
+}
+
+// negate returns the product of -1 and a term, negating its leading integer
+// coefficient if it has one.
+func (expr *Expr) negate() *Expr {
+	if negated, ok := expr.negateCoeff(); ok {
+		return negated
+	}
+	return NewNode(NewMul(), NewLeaf(NewInt(-1)), expr)
+}
+
+func (expr *Expr) negateCoeff() (*Expr, bool) {
+	switch expr.Token.Kind {
+	case KindInt:
+		return NewLeaf(NewBigInt(new(big.Int).Neg(expr.Token.BigInt()))), true
+	case KindMul:
+		if left, ok := expr.Args[0].negateCoeff(); ok {
+			return NewNode(expr.Token, left, expr.Args[1]), true
+		}
+	}
+	return nil, false
+}
+
+// signedTerm is a term of a sum with its sort keys.
+type signedTerm struct {
+	expr     *Expr
+	negative bool
+	mono     Monomial
+	isMono   bool
+	key      string
+}
+
+func (expr *Expr) appendTerms(terms []signedTerm, negative bool) []signedTerm {
+	switch expr.Token.Kind {
+	case KindPlus:
+		terms = expr.Args[0].appendTerms(terms, negative)
+		return expr.Args[1].appendTerms(terms, negative)
+	case KindMinus:
+		terms = expr.Args[0].appendTerms(terms, negative)
+		return expr.Args[1].appendTerms(terms, !negative)
+	}
+	return append(terms, signedTerm{expr: expr, negative: negative})
+}
+
+// monomial returns the monomial of a product of integers, variables and
+// their integer powers, or false for other expressions.
+func (expr *Expr) monomial() (Monomial, bool) {
+	switch expr.Token.Kind {
+	case KindInt:
+		return nil, true
+	case KindVar:
+		return Monomial{{Var: expr.Token.Value.(string), Exp: 1}}, true
+	case KindPow:
+		base, exp := expr.Args[0], expr.Args[1]
+		if base.Token.Kind != KindVar || exp.Token.Kind != KindInt || !exp.Token.BigInt().IsInt64() || exp.Token.BigInt().Int64() <= 0 || exp.Token.BigInt().Int64() > 1<<31-1 {
+			return nil, false
+		}
+		return Monomial{{Var: base.Token.Value.(string), Exp: int(exp.Token.BigInt().Int64())}}, true
+	case KindMul:
+		left, ok := expr.Args[0].monomial()
+		if !ok {
+			return nil, false
+		}
+		right, ok := expr.Args[1].monomial()
+		if !ok {
+			return nil, false
+		}
+		return left.Mul(right), true
+	}
+	return nil, false
+}
@@ -0,0 +1,85 @@
+// Copyright (c) 2017 Przemysław Dobrowolski
+//
+// This file is part of the math-mod, a package for symbolic manipulation
+// of large algebraic expressions.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package math
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Order Object", func() {
+	var poly Poly
+
+	BeforeEach(func() {
+		poly = expandString("x y^2 + x^2 z + z^3 + y + x^2")
+	})
+
+	Context("when terms are sorted in a named order", func() {
+		It("should print them in that order", func() {
+			Expect(poly.ExprIn(Lex).String()).To(Equal("x ^ 2 * z + x ^ 2 + x * y ^ 2 + y + z ^ 3"))
+			Expect(poly.ExprIn(Grlex).String()).To(Equal("x ^ 2 * z + x * y ^ 2 + z ^ 3 + x ^ 2 + y"))
+			Expect(poly.ExprIn(Grevlex).String()).To(Equal("x * y ^ 2 + x ^ 2 * z + z ^ 3 + x ^ 2 + y"))
+
+			deglex, err := ParseOrder("deglex")
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(deglex).To(Equal(Grlex))
+		})
+	})
+
+	Context("when variables are ranked", func() {
+		It("should compare them by rank", func() {
+			order, err := WithVars(Lex, []string{"z", "y"})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(order.String()).To(Equal("lex(z,y)"))
+			Expect(poly.ExprIn(order).String()).To(Equal("z ^ 3 + x ^ 2 * z + x * y ^ 2 + y + x ^ 2"))
+
+			order, err = WithVars(Grevlex, []string{"z", "y", "x"})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(poly.ExprIn(order).String()).To(Equal("z ^ 3 + x * y ^ 2 + x ^ 2 * z + x ^ 2 + y"))
+
+			order, err = WithVars(Grevlex, nil)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(poly.ExprIn(order).String()).To(Equal(poly.ExprIn(Grevlex).String()))
+
+			_, err = WithVars(Lex, []string{"x", "x"})
+			Expect(err).Should(HaveOccurred())
+		})
+	})
+
+	Context("when the terms of a tree are sorted", func() {
+		It("should not expand it", func() {
+			expr := parseTree("y - 3 x^2 + sin(x) + 2 (x + 1) - (z + x y)")
+			Expect(expr.SortTerms(Grlex).String()).To(Equal("-3 * x ^ 2 - x * y + y - z + 2 * ( x + 1 ) + sin ( x )"))
+
+			expr = parseTree("1 - x")
+			Expect(expr.SortTerms(Lex).String()).To(Equal("-1 * x + 1"))
+
+			expr = parseTree("y - 3 x^2 + z x")
+			Expect(expr.SortTerms(Lex).String()).To(Equal("-3 * x ^ 2 + z * x + y"))
+
+			expr = parseTree("z - x y 2")
+			Expect(expr.SortTerms(Lex).String()).To(Equal("-1 * ( x * y * 2 ) + z"))
+		})
+	})
+})
@@ -0,0 +1,150 @@
+// Copyright (c) 2017 Przemysław Dobrowolski
+//
+// This file is part of the math-mod, a package for symbolic manipulation
+// of large algebraic expressions.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package math
+
+import (
+	"context"
+	"math/big"
+	"sync"
+	"sync/atomic"
+)
+
+// parallelThreshold is the number of term pairs below which a product is not
+// worth splitting between goroutines.
+const parallelThreshold = 1 << 12
+
+// shardsPerJob is the number of shards each worker gets on average, so that
+// workers finishing early can pick up the remaining work.
+const shardsPerJob = 4
+
+// ExpandParallel works like ExpandContext, but multiplies large polynomials
+// with up to jobs goroutines. The result does not depend on jobs.
+func ExpandParallel(ctx context.Context, expr *Expr, limits Limits, jobs int) (Poly, error) {
+	return ExpandRing(ctx, expr, Integers, limits, jobs)
+}
+
+// ExpandRing works like ExpandParallel, taking the coefficients in the ring.
+func ExpandRing(ctx context.Context, expr *Expr, ring Ring, limits Limits, jobs int) (Poly, error) {
+	g := newGuard(ctx, limits)
+	g.jobs = jobs
+	return expand(expr, ring, g, 1)
+}
+
+// mulParallel splits the terms of poly into shards, multiplies each shard by
+// other in a pool of g.jobs workers and sums up the partial products in
+// shard order.
+func (poly Poly) mulParallel(other Poly, g *guard) (Poly, error) {
+	ctx, cancel := context.WithCancel(g.ctx)
+	defer cancel()
+
+	terms := poly.Terms()
+	shards := g.jobs * shardsPerJob
+	if shards > len(terms) {
+		shards = len(terms)
+	}
+
+	partials := make([]Poly, shards)
+	errs := make([]error, shards)
+	indices := make(chan int, shards)
+
+	for i := 0; i < shards; i++ {
+		indices <- i
+	}
+	close(indices)
+
+	// the workers count the terms held in all the partial products
+	// together, so the limits bound the product rather than each shard
+	var held int64
+	var wg sync.WaitGroup
+
+	for job := 0; job < g.jobs; job++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			worker := newGuard(ctx, g.limits)
+
+			for i := range indices {
+				shard := terms[i*len(terms)/shards : (i+1)*len(terms)/shards]
+
+				if partials[i], errs[i] = poly.mulShard(shard, other, worker, &held); errs[i] != nil {
+					cancel()
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	// a failing shard cancels the others, so report its error rather
+	// than the cancellation
+	for _, err := range errs {
+		if err != nil && err != context.Canceled {
+			return Poly{}, err
+		}
+	}
+
+	if err := g.ctx.Err(); err != nil {
+		return Poly{}, err
+	}
+
+	result := NewPolyRing(poly.Ring())
+
+	for _, partial := range partials {
+		for _, term := range partial.terms {
+			result.addTerm(term.Coeff, term.Mono)
+
+			if err := g.step(); err != nil {
+				return Poly{}, err
+			}
+			if err := g.checkTerms(len(result.terms), term.Coeff, term.Mono); err != nil {
+				return Poly{}, err
+			}
+		}
+	}
+	return result, nil
+}
+
+// mulShard multiplies the terms of a shard of poly by other. The terms it
+// adds to or merges in the partial product are counted in held, which is
+// shared by the workers and checked against the limits.
+func (poly Poly) mulShard(shard []Term, other Poly, g *guard, held *int64) (Poly, error) {
+	result := NewPolyRing(poly.Ring())
+	coeff := new(big.Rat)
+
+	for _, a := range shard {
+		for _, b := range other.terms {
+			before := len(result.terms)
+			mono := a.Mono.Mul(b.Mono)
+			result.addTerm(coeff.Mul(a.Coeff, b.Coeff), mono)
+			total := atomic.AddInt64(held, int64(len(result.terms)-before))
+
+			if err := g.step(); err != nil {
+				return Poly{}, err
+			}
+			if err := g.checkTerms(int(total), coeff, mono); err != nil {
+				return Poly{}, err
+			}
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,77 @@
+// Copyright (c) 2017 Przemysław Dobrowolski
+//
+// This file is part of the math-mod, a package for symbolic manipulation
+// of large algebraic expressions.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package math
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"context"
+)
+
+var _ = Describe("Parallel Object", func() {
+	expr := parseTree("(a + 2b - 3c + d + 5)^6 (a - b + 7c - d + e)^5")
+
+	Context("when a large product is expanded in parallel", func() {
+		It("should match the sequential result", func() {
+			sequential, err := Expand(expr)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			for _, jobs := range []int{2, 3, 8} {
+				parallel, err := ExpandParallel(context.Background(), expr, Limits{}, jobs)
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(parallel.Terms()).To(Equal(sequential.Terms()))
+			}
+		})
+	})
+
+	Context("when a shard exceeds the limits", func() {
+		It("should report the limit error", func() {
+			_, err := ExpandParallel(context.Background(), expr, Limits{MaxTerms: 1000}, 4)
+			Expect(err).To(Equal(&LimitError{Resource: "terms", Limit: 1000}))
+		})
+	})
+
+	Context("when the shards together exceed the limits", func() {
+		It("should report the limit error", func() {
+			poly, err := Expand(parseTree("a + b"))
+			Expect(err).ShouldNot(HaveOccurred())
+
+			held := int64(999)
+
+			_, err = poly.mulShard(poly.Terms(), poly, newGuard(context.Background(), Limits{MaxTerms: 1000}), &held)
+			Expect(err).To(Equal(&LimitError{Resource: "terms", Limit: 1000}))
+		})
+	})
+
+	Context("when the context is cancelled", func() {
+		It("should stop", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+
+			_, err := ExpandParallel(ctx, expr, Limits{}, 4)
+			Expect(err).To(Equal(context.Canceled))
+		})
+	})
+})
@@ -0,0 +1,261 @@
+// Copyright (c) 2017 Przemysław Dobrowolski
+//
+// This file is part of the math-mod, a package for symbolic manipulation
+// of large algebraic expressions.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package math
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"math/big"
+	"strings"
+)
+
+// ParseError describes a syntax error at a byte offset of the input.
+type ParseError struct {
+	Pos int
+	Msg string
+}
+
+func (err *ParseError) Error() string {
+	return fmt.Sprintf("position %d: %s", err.Pos, err.Msg)
+}
+
+// Parser parses infix input. The zero value uses the permissive implicit
+// multiplication policy.
+type Parser struct {
+	// Mul selects where multiplication may be implicit.
+	Mul MulPolicy
+	// Explain, if not nil, is called for every implicit multiplication of
+	// a parsed expression.
+	Explain func(ImplicitMul)
+}
+
+// Parse reads an infix expression, checks its syntax and returns its tree.
+// Syntax errors are reported as *ParseError.
+func Parse(reader io.Reader) (*Expr, error) {
+	return new(Parser).Parse(reader)
+}
+
+func ParseString(infix string) (*Expr, error) {
+	return Parse(strings.NewReader(infix))
+}
+
+// ParseLines reads one expression per line, skipping blank lines. Errors
+// are prefixed with the line number.
+func ParseLines(reader io.Reader) ([]*Expr, error) {
+	return new(Parser).ParseLines(reader)
+}
+
+func (parser *Parser) Parse(reader io.Reader) (*Expr, error) {
+	data, err := io.ReadAll(reader)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return parser.parse(data, 0)
+}
+
+func (parser *Parser) ParseString(infix string) (*Expr, error) {
+	return parser.Parse(strings.NewReader(infix))
+}
+
+func (parser *Parser) ParseLines(reader io.Reader) (exprs []*Expr, err error) {
+	scanner := bufio.NewScanner(reader)
+
+	for line := 1; scanner.Scan(); line++ {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+
+		expr, err := parser.ParseString(scanner.Text())
+
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", line, err)
+		}
+
+		exprs = append(exprs, expr)
+	}
+
+	return exprs, scanner.Err()
+}
+
+// ParseTable reads an infix expression into the table without building its
+// tree, so that identical subexpressions are stored once as they are read.
+// It returns the node of the whole expression.
+func (parser *Parser) ParseTable(reader io.Reader, table *Table) (NodeID, error) {
+	data, err := io.ReadAll(reader)
+
+	if err != nil {
+		return 0, err
+	}
+
+	postfix, muls, err := parser.postfix(data, 0)
+
+	if err != nil {
+		return 0, err
+	}
+
+	id, err := table.InternPostfix(postfix)
+
+	if err == nil {
+		parser.explain(muls)
+	}
+
+	return id, err
+}
+
+// parse parses the data found at the offset of the input, which positions
+// of errors and implicit multiplications are counted from.
+func (parser *Parser) parse(data []byte, offset int) (*Expr, error) {
+	postfix, muls, err := parser.postfix(data, offset)
+
+	if err != nil {
+		return nil, err
+	}
+
+	expr, err := ToTree(postfix)
+
+	if err == nil {
+		parser.explain(muls)
+	}
+
+	return expr, err
+}
+
+// postfix checks the syntax of the data found at the offset of the input
+// and returns it in postfix with the implicit multiplications inserted.
+func (parser *Parser) postfix(data []byte, offset int) (Tokens, []ImplicitMul, error) {
+	infix, positions, err := ParseInfixPositions(bytes.NewReader(data))
+
+	if err != nil {
+		return nil, nil, shiftError(err, offset)
+	}
+
+	for i := range positions {
+		positions[i] += offset
+	}
+
+	infix, positions, err = signNumbers(infix, positions)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	infix, positions, muls, err := insertMul(infix, positions, parser.Mul)
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err = checkInfix(infix, positions, offset+len(data)); err != nil {
+		return nil, nil, err
+	}
+
+	return ToPostfix(infix), muls, nil
+}
+
+func (parser *Parser) explain(muls []ImplicitMul) {
+	if parser.Explain != nil {
+		for _, mul := range muls {
+			parser.Explain(mul)
+		}
+	}
+}
+
+// signNumbers joins a minus sign written right before an integer into a
+// negative integer where an operand is expected, which is how negative
+// coefficients are printed. Raising such a number to a power is ambiguous.
+func signNumbers(infix Tokens, positions []int) (Tokens, []int, error) {
+	var result Tokens
+	var resultPositions []int
+
+	for i := 0; i < len(infix); i++ {
+		token, pos := infix[i], positions[i]
+
+		operand := len(result) == 0 || result[len(result)-1].Kind == KindOpen
+		if !operand {
+			_, operand = OperProps[result[len(result)-1].Kind]
+		}
+
+		if operand && token.Kind == KindMinus && i+1 < len(infix) && infix[i+1].Kind == KindInt && positions[i+1] == pos+1 {
+			i++
+			token = NewBigInt(new(big.Int).Neg(infix[i].BigInt()))
+
+			if i+1 < len(infix) && infix[i+1].Kind == KindPow {
+				return nil, nil, &ParseError{Pos: positions[i+1], Msg: fmt.Sprintf("ambiguous power of negative number: %v", token)}
+			}
+		}
+
+		result = append(result, token)
+		resultPositions = append(resultPositions, pos)
+	}
+
+	return result, resultPositions, nil
+}
+
+// checkInfix verifies that operators and brackets are placed correctly in
+// infix with multiplications already inserted.
+func checkInfix(infix Tokens, positions []int, end int) error {
+	var open []int
+	operand := true // whether an operand is expected next
+
+	for i, token := range infix {
+		switch token.Kind {
+		case KindInt, KindVar:
+			operand = false
+		case KindFunc:
+			if i+1 == len(infix) || infix[i+1].Kind != KindOpen {
+				return &ParseError{Pos: positions[i], Msg: fmt.Sprintf("missing argument for function: %v", token)}
+			}
+		case KindOpen:
+			open = append(open, positions[i])
+			operand = true
+		case KindClose:
+			if len(open) == 0 {
+				return &ParseError{Pos: positions[i], Msg: "unmatched closing bracket"}
+			}
+			if operand {
+				return &ParseError{Pos: positions[i], Msg: "missing operand before closing bracket"}
+			}
+			open = open[:len(open)-1]
+		default:
+			if operand {
+				return &ParseError{Pos: positions[i], Msg: fmt.Sprintf("missing operand for operator: %v", token)}
+			}
+			operand = true
+		}
+	}
+
+	if operand {
+		return &ParseError{Pos: end, Msg: "unexpected end of input"}
+	}
+
+	if len(open) > 0 {
+		return &ParseError{Pos: open[len(open)-1], Msg: "unclosed bracket"}
+	}
+
+	return nil
+}
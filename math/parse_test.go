@@ -0,0 +1,90 @@
+// Copyright (c) 2017 Przemysław Dobrowolski
+//
+// This file is part of the math-mod, a package for symbolic manipulation
+// of large algebraic expressions.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package math
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"strings"
+)
+
+func parseErrorPos(infix string) int {
+	_, err := ParseString(infix)
+	Expect(err).Should(BeAssignableToTypeOf(&ParseError{}))
+	return err.(*ParseError).Pos
+}
+
+var _ = Describe("Parse Object", func() {
+	Context("when input is valid", func() {
+		It("should build the tree", func() {
+			expr, err := ParseString("2 (x + 1) sin(y)")
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(expr.String()).To(Equal("2 * ( x + 1 ) * sin ( y )"))
+		})
+	})
+
+	Context("when a minus sign precedes an integer operand", func() {
+		It("should read a negative integer", func() {
+			expr, err := ParseString("-3 * x ^ ( -2 ) - 1")
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(expr.Equal(NewNode(NewMinus(),
+				NewNode(NewMul(), NewLeaf(NewInt(-3)), NewNode(NewPow(), NewLeaf(NewVar("x")), NewLeaf(NewInt(-2)))),
+				NewLeaf(NewInt(1))))).To(BeTrue())
+			Expect(parseErrorPos("- x")).To(Equal(0))
+			Expect(parseErrorPos("-3 ^ 2")).To(Equal(3))
+		})
+	})
+
+	Context("when input is invalid", func() {
+		It("should report the position", func() {
+			Expect(parseErrorPos("x % y")).To(Equal(2))
+			Expect(parseErrorPos("x + * y")).To(Equal(4))
+			Expect(parseErrorPos("(x + y))")).To(Equal(7))
+			Expect(parseErrorPos("x + (y - ")).To(Equal(9))
+			Expect(parseErrorPos("x + (y (z)")).To(Equal(4))
+			Expect(parseErrorPos("sin x")).To(Equal(0))
+			Expect(parseErrorPos("")).To(Equal(0))
+		})
+	})
+
+	Context("when a token crosses the scanner buffer", func() {
+		It("should not be split", func() {
+			infix := strings.Repeat(" ", 4090) + "abcdefghij + 1"
+			tokens, positions, err := ParseInfixPositions(strings.NewReader(infix))
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(tokens).To(Equal(Tokens{NewVar("abcdefghij"), NewPlus(), NewInt(1)}))
+			Expect(positions).To(Equal([]int{4090, 4101, 4103}))
+		})
+	})
+
+	Context("when an integer exceeds int64", func() {
+		It("should stay an integer", func() {
+			tokens, err := ParseInfixString("123456789012345678901234567890")
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(tokens[0].Kind).To(Equal(Kind(KindInt)))
+			Expect(tokens[0].String()).To(Equal("123456789012345678901234567890"))
+		})
+	})
+})
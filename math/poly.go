@@ -0,0 +1,615 @@
+// Copyright (c) 2017 Przemysław Dobrowolski
+//
+// This file is part of the math-mod, a package for symbolic manipulation
+// of large algebraic expressions.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package math
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+)
+
+// Power is a variable raised to a positive integer exponent.
+type Power struct {
+	Var string
+	Exp int
+}
+
+// Monomial is a product of powers sorted by variable name. An empty monomial
+// stands for the constant 1.
+type Monomial []Power
+
+func (mono Monomial) Degree() (degree int) {
+	for _, power := range mono {
+		degree += power.Exp
+	}
+	return
+}
+
+func (mono Monomial) Mul(other Monomial) (result Monomial) {
+	i, j := 0, 0
+
+	for i < len(mono) && j < len(other) {
+		switch {
+		case mono[i].Var < other[j].Var:
+			result = append(result, mono[i])
+			i++
+		case mono[i].Var > other[j].Var:
+			result = append(result, other[j])
+			j++
+		default:
+			result = append(result, Power{Var: mono[i].Var, Exp: mono[i].Exp + other[j].Exp})
+			i++
+			j++
+		}
+	}
+	result = append(result, mono[i:]...)
+	return append(result, other[j:]...)
+}
+
+// Div divides the monomials, reporting false if other does not divide mono.
+func (mono Monomial) Div(other Monomial) (result Monomial, ok bool) {
+	j := 0
+
+	for _, power := range mono {
+		exp := power.Exp
+		if j < len(other) && other[j].Var == power.Var {
+			exp -= other[j].Exp
+			j++
+		}
+		switch {
+		case exp < 0:
+			return nil, false
+		case exp > 0:
+			result = append(result, Power{Var: power.Var, Exp: exp})
+		}
+	}
+	return result, j == len(other)
+}
+
+// Lcm returns the least common multiple of the monomials.
+func (mono Monomial) Lcm(other Monomial) (result Monomial) {
+	i, j := 0, 0
+
+	for i < len(mono) || j < len(other) {
+		switch {
+		case j == len(other) || i < len(mono) && mono[i].Var < other[j].Var:
+			result = append(result, mono[i])
+			i++
+		case i == len(mono) || mono[i].Var > other[j].Var:
+			result = append(result, other[j])
+			j++
+		default:
+			power := mono[i]
+			if other[j].Exp > power.Exp {
+				power = other[j]
+			}
+			result = append(result, power)
+			i++
+			j++
+		}
+	}
+	return
+}
+
+// without returns the monomial with the variable removed.
+func (mono Monomial) without(name string) (result Monomial) {
+	for _, power := range mono {
+		if power.Var != name {
+			result = append(result, power)
+		}
+	}
+	return
+}
+
+// Exp returns the exponent of the variable in the monomial, 0 if absent.
+func (mono Monomial) Exp(name string) int {
+	for _, power := range mono {
+		if power.Var == name {
+			return power.Exp
+		}
+	}
+	return 0
+}
+
+func (mono Monomial) String() string {
+	var parts []string
+
+	for _, power := range mono {
+		if power.Exp == 1 {
+			parts = append(parts, power.Var)
+		} else {
+			parts = append(parts, fmt.Sprintf("%s^%d", power.Var, power.Exp))
+		}
+	}
+	return strings.Join(parts, "*")
+}
+
+// Term is a monomial with its coefficient.
+type Term struct {
+	Coeff *big.Rat
+	Mono  Monomial
+}
+
+// Expr converts the term to a product of its coefficient, omitted if it is
+// 1, and powers of variables.
+func (term Term) Expr() *Expr {
+	var product *Expr
+	if term.Coeff.Cmp(big.NewRat(1, 1)) != 0 || len(term.Mono) == 0 {
+		product = NewLeaf(NewBigInt(term.Coeff.Num()))
+		if !term.Coeff.IsInt() {
+			product = NewNode(NewDiv(), product, NewLeaf(NewBigInt(term.Coeff.Denom())))
+		}
+	}
+	for _, power := range term.Mono {
+		factor := NewLeaf(NewVar(power.Var))
+		if power.Exp != 1 {
+			factor = NewNode(NewPow(), factor, NewLeaf(NewInt(int64(power.Exp))))
+		}
+		if product == nil {
+			product = factor
+		} else {
+			product = NewNode(NewMul(), product, factor)
+		}
+	}
+	return product
+}
+
+// Poly is a multivariate polynomial in expanded form with coefficients in a
+// ring. Terms are keyed by the string form of their monomials.
+type Poly struct {
+	ring  Ring
+	terms map[string]Term
+}
+
+// NewPoly returns the zero polynomial over the integers.
+func NewPoly() Poly {
+	return NewPolyRing(Integers)
+}
+
+func NewPolyRing(ring Ring) Poly {
+	return Poly{
+		ring:  ring,
+		terms: make(map[string]Term),
+	}
+}
+
+func NewPolyInt(value *big.Int) Poly {
+	return newPolyConst(Integers, new(big.Rat).SetInt(value))
+}
+
+func NewPolyVar(name string) Poly {
+	return newPolyVar(Integers, name)
+}
+
+func newPolyConst(ring Ring, value *big.Rat) Poly {
+	poly := NewPolyRing(ring)
+	poly.addTerm(value, nil)
+	return poly
+}
+
+func newPolyVar(ring Ring, name string) Poly {
+	poly := NewPolyRing(ring)
+	poly.addTerm(big.NewRat(1, 1), Monomial{{Var: name, Exp: 1}})
+	return poly
+}
+
+// Ring returns the ring of the coefficients.
+func (poly Poly) Ring() Ring {
+	if poly.ring == nil {
+		return Integers
+	}
+	return poly.ring
+}
+
+// To maps the coefficients into another ring. It fails if a coefficient
+// does not belong to it.
+func (poly Poly) To(ring Ring) (Poly, error) {
+	result := NewPolyRing(ring)
+
+	for _, term := range poly.terms {
+		coeff, ok := ring.Reduce(term.Coeff)
+		if !ok {
+			return Poly{}, fmt.Errorf("coefficient %v does not belong to %v", term.Coeff.RatString(), ring)
+		}
+		result.addTerm(coeff, term.Mono)
+	}
+	return result, nil
+}
+
+// reduce brings a value computed from coefficients of the polynomial to the
+// canonical form of its ring.
+func (poly Poly) reduce(value *big.Rat) *big.Rat {
+	result, ok := poly.Ring().Reduce(value)
+
+	if !ok {
+		panic(fmt.Sprintf("coefficient %v does not belong to %v", value.RatString(), poly.Ring()))
+	}
+
+	return result
+}
+
+// addTerm accumulates coeff*mono in place; it must only be used on
+// polynomials that have not been handed out yet.
+func (poly Poly) addTerm(coeff *big.Rat, mono Monomial) {
+	key := mono.String()
+
+	if term, ok := poly.terms[key]; ok {
+		sum := poly.reduce(new(big.Rat).Add(term.Coeff, coeff))
+		if sum.Sign() == 0 {
+			delete(poly.terms, key)
+		} else {
+			poly.terms[key] = Term{Coeff: sum, Mono: term.Mono}
+		}
+	} else if coeff = poly.reduce(coeff); coeff.Sign() != 0 {
+		poly.terms[key] = Term{Coeff: coeff, Mono: mono}
+	}
+}
+
+func (poly Poly) Len() int {
+	return len(poly.terms)
+}
+
+func (poly Poly) IsZero() bool {
+	return len(poly.terms) == 0
+}
+
+// Const returns the value of a constant polynomial.
+func (poly Poly) Const() (*big.Rat, bool) {
+	switch len(poly.terms) {
+	case 0:
+		return new(big.Rat), true
+	case 1:
+		if term, ok := poly.terms[""]; ok {
+			return new(big.Rat).Set(term.Coeff), true
+		}
+	}
+	return nil, false
+}
+
+// Int returns the value of a constant polynomial with an integer value.
+func (poly Poly) Int() (*big.Int, bool) {
+	if value, ok := poly.Const(); ok && value.IsInt() {
+		return new(big.Int).Set(value.Num()), true
+	}
+	return nil, false
+}
+
+// Equal reports whether both polynomials have the same terms.
+func (poly Poly) Equal(other Poly) bool {
+	if len(poly.terms) != len(other.terms) {
+		return false
+	}
+
+	for key, term := range poly.terms {
+		if otherTerm, ok := other.terms[key]; !ok || term.Coeff.Cmp(otherTerm.Coeff) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func (poly Poly) Degree() (degree int) {
+	for _, term := range poly.terms {
+		if d := term.Mono.Degree(); d > degree {
+			degree = d
+		}
+	}
+	return
+}
+
+func (poly Poly) Add(other Poly) Poly {
+	result, _ := poly.add(other, nil)
+	return result
+}
+
+func (poly Poly) add(other Poly, g *guard) (Poly, error) {
+	result := NewPolyRing(poly.Ring())
+
+	for _, term := range poly.terms {
+		result.addTerm(term.Coeff, term.Mono)
+	}
+	for _, term := range other.terms {
+		result.addTerm(term.Coeff, term.Mono)
+		if err := g.checkTerms(len(result.terms), term.Coeff, term.Mono); err != nil {
+			return Poly{}, err
+		}
+	}
+	return result, nil
+}
+
+func (poly Poly) Neg() Poly {
+	result := NewPolyRing(poly.Ring())
+
+	for _, term := range poly.terms {
+		result.addTerm(new(big.Rat).Neg(term.Coeff), term.Mono)
+	}
+	return result
+}
+
+func (poly Poly) Sub(other Poly) Poly {
+	return poly.Add(other.Neg())
+}
+
+func (poly Poly) Mul(other Poly) Poly {
+	result, _ := poly.mul(other, nil)
+	return result
+}
+
+func (poly Poly) mul(other Poly, g *guard) (Poly, error) {
+	if g != nil && g.jobs > 1 && len(poly.terms)*len(other.terms) >= parallelThreshold {
+		return poly.mulParallel(other, g)
+	}
+
+	result := NewPolyRing(poly.Ring())
+	coeff := new(big.Rat)
+
+	for _, a := range poly.terms {
+		for _, b := range other.terms {
+			mono := a.Mono.Mul(b.Mono)
+			result.addTerm(coeff.Mul(a.Coeff, b.Coeff), mono)
+
+			if err := g.step(); err != nil {
+				return Poly{}, err
+			}
+			if err := g.checkTerms(len(result.terms), coeff, mono); err != nil {
+				return Poly{}, err
+			}
+		}
+	}
+	return result, nil
+}
+
+func (poly Poly) Pow(exp int) Poly {
+	result, _ := poly.pow(exp, nil)
+	return result
+}
+
+func (poly Poly) pow(exp int, g *guard) (Poly, error) {
+	result := newPolyConst(poly.Ring(), big.NewRat(1, 1))
+	base := poly
+
+	for exp > 0 {
+		var err error
+		if exp&1 == 1 {
+			if result, err = result.mul(base, g); err != nil {
+				return Poly{}, err
+			}
+		}
+		exp >>= 1
+		if exp > 0 {
+			if base, err = base.mul(base, g); err != nil {
+				return Poly{}, err
+			}
+		}
+	}
+	return result, nil
+}
+
+// divConst divides every coefficient by a constant of the ring.
+func (poly Poly) divConst(divisor *big.Rat) (Poly, error) {
+	if divisor.Sign() == 0 {
+		return Poly{}, fmt.Errorf("division by zero")
+	}
+
+	result := NewPolyRing(poly.Ring())
+
+	for _, term := range poly.terms {
+		coeff, ok := poly.Ring().Reduce(new(big.Rat).Quo(term.Coeff, divisor))
+		if !ok {
+			return Poly{}, fmt.Errorf("%v is not divisible by %v in %v", term.Coeff.RatString(), divisor.RatString(), poly.Ring())
+		}
+		result.addTerm(coeff, term.Mono)
+	}
+	return result, nil
+}
+
+// Diff returns the partial derivative with respect to the variable.
+func (poly Poly) Diff(name string) Poly {
+	result := NewPolyRing(poly.Ring())
+	coeff := new(big.Rat)
+
+	for _, term := range poly.terms {
+		exp := term.Mono.Exp(name)
+		if exp == 0 {
+			continue
+		}
+
+		var mono Monomial
+		for _, power := range term.Mono {
+			if power.Var != name {
+				mono = append(mono, power)
+			} else if power.Exp > 1 {
+				mono = append(mono, Power{Var: name, Exp: power.Exp - 1})
+			}
+		}
+		result.addTerm(coeff.Mul(term.Coeff, big.NewRat(int64(exp), 1)), mono)
+	}
+	return result
+}
+
+// Terms returns the terms by descending total degree, ties broken
+// lexicographically with variables in alphabetical order.
+func (poly Poly) Terms() []Term {
+	return poly.TermsIn(Grlex)
+}
+
+// TermsIn returns the terms in descending monomial order.
+func (poly Poly) TermsIn(order Order) []Term {
+	terms := make([]Term, 0, len(poly.terms))
+
+	for _, term := range poly.terms {
+		terms = append(terms, term)
+	}
+	sort.Slice(terms, func(i, j int) bool {
+		return order.Compare(terms[i].Mono, terms[j].Mono) > 0
+	})
+	return terms
+}
+
+func compareGrlex(a, b Monomial) int {
+	if da, db := a.Degree(), b.Degree(); da != db {
+		if da > db {
+			return 1
+		}
+		return -1
+	}
+	return compareLex(a, b)
+}
+
+func compareLex(a, b Monomial) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		switch {
+		case a[i].Var < b[i].Var:
+			return 1
+		case a[i].Var > b[i].Var:
+			return -1
+		case a[i].Exp > b[i].Exp:
+			return 1
+		case a[i].Exp < b[i].Exp:
+			return -1
+		}
+	}
+	switch {
+	case len(a) > len(b):
+		return 1
+	case len(a) < len(b):
+		return -1
+	}
+	return 0
+}
+
+// Expr converts the polynomial back to an expression tree, one product per
+// term joined by + and -. Fractional coefficients become quotients.
+func (poly Poly) Expr() *Expr {
+	return poly.ExprIn(Grlex)
+}
+
+// ExprIn works like Expr with the terms in descending monomial order.
+func (poly Poly) ExprIn(order Order) *Expr {
+	var result *Expr
+
+	for _, term := range poly.TermsIn(order) {
+		coeff := term.Coeff
+		if result != nil {
+			coeff = new(big.Rat).Abs(coeff)
+		}
+
+		product := Term{Coeff: coeff, Mono: term.Mono}.Expr()
+
+		switch {
+		case result == nil:
+			result = product
+		case term.Coeff.Sign() < 0:
+			result = NewNode(NewMinus(), result, product)
+		default:
+			result = NewNode(NewPlus(), result, product)
+		}
+	}
+
+	if result == nil {
+		return NewLeaf(NewInt(0))
+	}
+	return result
+}
+
+func (poly Poly) String() string {
+	return poly.Expr().String()
+}
+
+// Expand multiplies out all products and integer powers of the expression.
+func Expand(expr *Expr) (Poly, error) {
+	return expand(expr, Integers, nil, 1)
+}
+
+// ExpandContext works like Expand, but gives up when the context is done or
+// the limits are exceeded; the latter is reported as *LimitError.
+func ExpandContext(ctx context.Context, expr *Expr, limits Limits) (Poly, error) {
+	return expand(expr, Integers, newGuard(ctx, limits), 1)
+}
+
+func expand(expr *Expr, ring Ring, g *guard, depth int) (Poly, error) {
+	if err := g.checkDepth(depth); err != nil {
+		return Poly{}, err
+	}
+
+	if err := g.step(); err != nil {
+		return Poly{}, err
+	}
+
+	switch expr.Token.Kind {
+	case KindInt:
+		value, ok := ring.Reduce(new(big.Rat).SetInt(expr.Token.BigInt()))
+		if !ok {
+			return Poly{}, fmt.Errorf("integer %v does not belong to %v", expr.Token, ring)
+		}
+		return newPolyConst(ring, value), nil
+	case KindVar:
+		return newPolyVar(ring, expr.Token.Value.(string)), nil
+	}
+
+	if len(expr.Args) != 2 {
+		return Poly{}, fmt.Errorf("unexpected token: %v", expr.Token)
+	}
+
+	left, err := expand(expr.Args[0], ring, g, depth+1)
+	if err != nil {
+		return Poly{}, err
+	}
+
+	// exponents are integers whatever the ring of the coefficients
+	exponentRing := ring
+	if expr.Token.Kind == KindPow {
+		exponentRing = Integers
+	}
+
+	right, err := expand(expr.Args[1], exponentRing, g, depth+1)
+	if err != nil {
+		return Poly{}, err
+	}
+
+	switch expr.Token.Kind {
+	case KindPlus:
+		return left.add(right, g)
+	case KindMinus:
+		return left.add(right.Neg(), g)
+	case KindMul:
+		return left.mul(right, g)
+	case KindDiv:
+		divisor, ok := right.Const()
+		if !ok {
+			return Poly{}, fmt.Errorf("division by a non-constant polynomial: %v", expr.Args[1])
+		}
+		return left.divConst(divisor)
+	case KindPow:
+		exp, ok := right.Int()
+		if !ok || !exp.IsInt64() || exp.Sign() < 0 || exp.Int64() > 1<<31-1 {
+			return Poly{}, fmt.Errorf("exponent is not a non-negative integer: %v", expr.Args[1])
+		}
+		return left.pow(int(exp.Int64()), g)
+	}
+
+	return Poly{}, fmt.Errorf("unexpected token: %v", expr.Token)
+}
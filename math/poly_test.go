@@ -0,0 +1,84 @@
+// Copyright (c) 2017 Przemysław Dobrowolski
+//
+// This file is part of the math-mod, a package for symbolic manipulation
+// of large algebraic expressions.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package math
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func expandString(infix string) Poly {
+	poly, err := Expand(parseTree(infix))
+	Expect(err).ShouldNot(HaveOccurred())
+	return poly
+}
+
+var _ = Describe("Poly Object", func() {
+	Context("when a product of sums is expanded", func() {
+		It("should collect like terms", func() {
+			Expect(expandString("(x + y)^2 - 2 x y").String()).To(Equal("x ^ 2 + y ^ 2"))
+			Expect(expandString("(x - 1)(x + 1)").String()).To(Equal("x ^ 2 - 1"))
+		})
+	})
+
+	Context("when terms cancel out", func() {
+		It("should give zero", func() {
+			poly := expandString("(a + b)(a - b) - a^2 + b^2")
+			Expect(poly.IsZero()).To(BeTrue())
+			Expect(poly.String()).To(Equal("0"))
+		})
+	})
+
+	Context("when the leading coefficient is negative", func() {
+		It("should keep the sign on the first term", func() {
+			Expect(expandString("1 - 3 x^2").String()).To(Equal("-3 * x ^ 2 + 1"))
+		})
+
+		It("should be read back", func() {
+			poly := expandString("y - 3 x^2 - 2 x y")
+			expr, err := ParseString(poly.String())
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(expr.Equal(poly.Expr())).To(BeTrue())
+		})
+	})
+
+	Context("when coefficients exceed int64", func() {
+		It("should not overflow", func() {
+			Expect(expandString("(10 x)^20").String()).To(Equal("100000000000000000000 * x ^ 20"))
+		})
+	})
+
+	Context("when the exponent is not an integer", func() {
+		It("should fail", func() {
+			_, err := Expand(parseTree("x^y"))
+			Expect(err).Should(HaveOccurred())
+		})
+	})
+
+	Context("when a polynomial is differentiated", func() {
+		It("should differentiate each term", func() {
+			Expect(expandString("x^3 y + 2 x y^2 + y").Diff("x").String()).To(Equal("3 * x ^ 2 * y + 2 * y ^ 2"))
+		})
+	})
+})
@@ -23,22 +23,34 @@
 
 package math
 
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math/big"
+	"strings"
+)
+
 func ToPostfix(infix Tokens) (postfix Tokens) {
 	var stack []Token
 
 	for _, token := range infix {
 		switch token.Kind {
-		case KindOpen:
+		case KindOpen, KindFunc:
 			stack = append(stack, token)
 		case KindClose:
 			var op Token
-			for {
+			for len(stack) > 0 {
 				op, stack = stack[len(stack)-1], stack[:len(stack)-1]
 				if op.Kind == KindOpen {
 					break
 				}
 				postfix = append(postfix, op)
 			}
+			if len(stack) > 0 && stack[len(stack)-1].Kind == KindFunc {
+				postfix = append(postfix, stack[len(stack)-1])
+				stack = stack[:len(stack)-1]
+			}
 		default:
 			if operPropA, isOperA := OperProps[token.Kind]; isOperA {
 				for len(stack) > 0 {
@@ -61,3 +73,127 @@ func ToPostfix(infix Tokens) (postfix Tokens) {
 	}
 	return
 }
+
+// lexNotation splits input in a notation such as postfix into tokens. A
+// minus sign written right before an integer is taken as its sign, since
+// these notations print tokens apart and have no unary minus.
+func lexNotation(data []byte) (Tokens, []int, error) {
+	tokens, positions, err := ParseInfixPositions(bytes.NewReader(data))
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var resultTokens Tokens
+	var resultPositions []int
+
+	for i := 0; i < len(tokens); i++ {
+		token, pos := tokens[i], positions[i]
+
+		if token.Kind == KindMinus && i+1 < len(tokens) && tokens[i+1].Kind == KindInt && positions[i+1] == positions[i]+1 {
+			i++
+			token = NewBigInt(new(big.Int).Neg(tokens[i].BigInt()))
+		}
+
+		resultTokens = append(resultTokens, token)
+		resultPositions = append(resultPositions, pos)
+	}
+
+	return resultTokens, resultPositions, nil
+}
+
+// unbracket blanks out square brackets enclosing the data, as printed
+// around tokens, keeping positions of the rest.
+func unbracket(data []byte) []byte {
+	if trimmed := bytes.TrimSpace(data); len(trimmed) >= 2 && trimmed[0] == '[' && trimmed[len(trimmed)-1] == ']' {
+		data = append([]byte(nil), data...)
+		data[bytes.IndexByte(data, '[')] = ' '
+		data[bytes.LastIndexByte(data, ']')] = ' '
+	}
+	return data
+}
+
+// ParsePostfix reads an expression in postfix (RPN) notation, as printed by
+// Postfix, optionally enclosed in square brackets. Every operator must find
+// its operands on the stack and exactly one expression must be left on it;
+// syntax errors are reported as *ParseError.
+func ParsePostfix(reader io.Reader) (*Expr, error) {
+	data, err := io.ReadAll(reader)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return parsePostfix(data, 0)
+}
+
+func ParsePostfixString(postfix string) (*Expr, error) {
+	return ParsePostfix(strings.NewReader(postfix))
+}
+
+// ParsePostfixStatements reads statements in postfix as printed by format
+// --to postfix, one per line or separated by ';': expressions, assignments
+// name := expr and equations lhs = rhs. Like ParseStatements it reports
+// syntax errors in Err of each statement.
+func ParsePostfixStatements(reader io.Reader) ([]Statement, error) {
+	data, err := io.ReadAll(reader)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return parseLines(data, func(data []byte, start, end int) (*Expr, error) {
+		return parsePostfix(data[start:end], start)
+	}), nil
+}
+
+// parsePostfix parses the data found at the offset of the input, which
+// positions of errors are counted from.
+func parsePostfix(data []byte, offset int) (*Expr, error) {
+	postfix, positions, err := lexNotation(unbracket(data))
+
+	if err != nil {
+		return nil, shiftError(err, offset)
+	}
+
+	for i := range positions {
+		positions[i] += offset
+	}
+
+	var stack []*Expr
+	// positions of the first token of the expressions on the stack
+	var starts []int
+
+	for i, token := range postfix {
+		pos := positions[i]
+
+		switch token.Kind {
+		case KindInt, KindVar:
+			stack = append(stack, NewLeaf(token))
+			starts = append(starts, pos)
+		case KindFunc:
+			if len(stack) < 1 {
+				return nil, &ParseError{Pos: pos, Msg: fmt.Sprintf("missing argument for function: %v", token)}
+			}
+			stack[len(stack)-1] = NewNode(token, stack[len(stack)-1])
+		case KindOpen, KindClose:
+			return nil, &ParseError{Pos: pos, Msg: "unexpected bracket in postfix"}
+		default:
+			if len(stack) < 2 {
+				return nil, &ParseError{Pos: pos, Msg: fmt.Sprintf("missing operand for operator: %v", token)}
+			}
+			left, right := stack[len(stack)-2], stack[len(stack)-1]
+			stack = append(stack[:len(stack)-2], NewNode(token, left, right))
+			starts = starts[:len(starts)-1]
+		}
+	}
+
+	switch len(stack) {
+	case 0:
+		return nil, &ParseError{Pos: offset + len(data), Msg: "empty expression"}
+	case 1:
+		return stack[0], nil
+	default:
+		return nil, &ParseError{Pos: starts[1], Msg: fmt.Sprintf("missing operator: %d operands left", len(stack))}
+	}
+}
@@ -24,17 +24,13 @@
 package math
 
 import (
+	"fmt"
+	"strings"
+
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
-
-	"testing"
 )
 
-func TestPostfix(t *testing.T) {
-	RegisterFailHandler(Fail)
-	RunSpecs(t, "Postfix Suite")
-}
-
 var _ = Describe("Postfix Object", func() {
 	var (
 		infix Tokens
@@ -69,4 +65,63 @@ var _ = Describe("Postfix Object", func() {
 			}))
 		})
 	})
+
+	Context("when postfix is joined", func() {
+		It("should separate the tokens by spaces", func() {
+			Expect(Tokens{NewInt(3), NewVar("x"), NewMul()}.Join()).To(Equal("3 x *"))
+			Expect(Tokens{}.Join()).To(Equal(""))
+		})
+	})
+
+	Context("when postfix is parsed", func() {
+		It("should build the tree", func() {
+			expr, err := ParsePostfixString("3 4 2 * + 1 5 - 2 3 ^ ^ - x sin *")
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(expr.String()).To(Equal("( 3 + 4 * 2 - ( 1 - 5 ) ^ 2 ^ 3 ) * sin ( x )"))
+		})
+
+		It("should read printed postfix back", func() {
+			expr := parseTree("(a[1] - b) / c ^ 2")
+
+			parsed, err := ParsePostfixString(fmt.Sprint(expr.Postfix()))
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(parsed.Equal(expr)).To(BeTrue())
+
+			parsed, err = ParsePostfixString("x -3 * 2 -")
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(parsed).To(Equal(NewNode(NewMinus(), NewNode(NewMul(), NewLeaf(NewVar("x")), NewLeaf(NewInt(-3))), NewLeaf(NewInt(2)))))
+		})
+
+		It("should check the stack", func() {
+			_, err := ParsePostfixString("x y + *")
+			Expect(err).To(Equal(&ParseError{Pos: 6, Msg: "missing operand for operator: *"}))
+
+			_, err = ParsePostfixString("sin")
+			Expect(err).To(Equal(&ParseError{Pos: 0, Msg: "missing argument for function: sin"}))
+
+			_, err = ParsePostfixString("x y z +")
+			Expect(err).To(Equal(&ParseError{Pos: 2, Msg: "missing operator: 2 operands left"}))
+
+			_, err = ParsePostfixString("x ( y +")
+			Expect(err).To(Equal(&ParseError{Pos: 2, Msg: "unexpected bracket in postfix"}))
+
+			_, err = ParsePostfixString(" [ ] ")
+			Expect(err).To(Equal(&ParseError{Pos: 5, Msg: "empty expression"}))
+		})
+
+		It("should read statements as printed", func() {
+			stmts, err := ParsePostfixStatements(strings.NewReader("a := [x 1 +]\n[a 2 ^] = [y]\nx y + *; x -3 *\n"))
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(stmts).To(HaveLen(4))
+
+			Expect(stmts[0].Name).To(Equal("a"))
+			Expect(stmts[0].Expr.String()).To(Equal("x + 1"))
+			Expect(stmts[1].Expr.String()).To(Equal("a ^ 2"))
+			Expect(stmts[1].Rhs.String()).To(Equal("y"))
+			Expect(stmts[1].Line).To(Equal(2))
+			Expect(stmts[2].Err).To(Equal(&ParseError{Pos: 33, Msg: "missing operand for operator: *"}))
+			Expect(stmts[3].Expr.String()).To(Equal("x * ( -3 )"))
+			Expect(stmts[3].Line).To(Equal(3))
+		})
+	})
 })
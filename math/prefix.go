@@ -0,0 +1,129 @@
+// Copyright (c) 2017 Przemysław Dobrowolski
+//
+// This file is part of the math-mod, a package for symbolic manipulation
+// of large algebraic expressions.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package math
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Prefix returns the expression in prefix (Polish) notation.
+func (expr *Expr) Prefix() Tokens {
+	return expr.appendPrefix(nil)
+}
+
+func (expr *Expr) appendPrefix(prefix Tokens) Tokens {
+	prefix = append(prefix, expr.Token)
+	for _, arg := range expr.Args {
+		prefix = arg.appendPrefix(prefix)
+	}
+	return prefix
+}
+
+// ParsePrefix reads an expression in prefix (Polish) notation, as printed
+// by Prefix, optionally enclosed in square brackets. Syntax errors are
+// reported as *ParseError.
+func ParsePrefix(reader io.Reader) (*Expr, error) {
+	data, err := io.ReadAll(reader)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return parsePrefix(data, 0)
+}
+
+func ParsePrefixString(prefix string) (*Expr, error) {
+	return ParsePrefix(strings.NewReader(prefix))
+}
+
+// ParsePrefixStatements reads statements in prefix as printed by format
+// --to prefix, one per line or separated by ';': expressions, assignments
+// name := expr and equations lhs = rhs. Like ParseStatements it reports
+// syntax errors in Err of each statement.
+func ParsePrefixStatements(reader io.Reader) ([]Statement, error) {
+	data, err := io.ReadAll(reader)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return parseLines(data, func(data []byte, start, end int) (*Expr, error) {
+		return parsePrefix(data[start:end], start)
+	}), nil
+}
+
+// parsePrefix parses the data found at the offset of the input, which
+// positions of errors are counted from.
+func parsePrefix(data []byte, offset int) (*Expr, error) {
+	prefix, positions, err := lexNotation(unbracket(data))
+
+	if err != nil {
+		return nil, shiftError(err, offset)
+	}
+
+	for i := range positions {
+		positions[i] += offset
+	}
+
+	// read backwards, operators take their operands from the stack with
+	// the left one on top
+	var stack []*Expr
+	var starts []int
+
+	for i := len(prefix) - 1; i >= 0; i-- {
+		token, pos := prefix[i], positions[i]
+
+		switch token.Kind {
+		case KindInt, KindVar:
+			stack = append(stack, NewLeaf(token))
+			starts = append(starts, pos)
+		case KindFunc:
+			if len(stack) < 1 {
+				return nil, &ParseError{Pos: pos, Msg: fmt.Sprintf("missing argument for function: %v", token)}
+			}
+			stack[len(stack)-1] = NewNode(token, stack[len(stack)-1])
+			starts[len(starts)-1] = pos
+		case KindOpen, KindClose:
+			return nil, &ParseError{Pos: pos, Msg: "unexpected bracket in prefix"}
+		default:
+			if len(stack) < 2 {
+				return nil, &ParseError{Pos: pos, Msg: fmt.Sprintf("missing operand for operator: %v", token)}
+			}
+			left, right := stack[len(stack)-1], stack[len(stack)-2]
+			stack = append(stack[:len(stack)-2], NewNode(token, left, right))
+			starts = append(starts[:len(starts)-2], pos)
+		}
+	}
+
+	switch len(stack) {
+	case 0:
+		return nil, &ParseError{Pos: offset + len(data), Msg: "empty expression"}
+	case 1:
+		return stack[0], nil
+	default:
+		return nil, &ParseError{Pos: starts[len(starts)-2], Msg: fmt.Sprintf("missing operator: %d operands left", len(stack))}
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright (c) 2017 Przemysław Dobrowolski
+//
+// This file is part of the math-mod, a package for symbolic manipulation
+// of large algebraic expressions.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package math
+
+import (
+	"fmt"
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Prefix Object", func() {
+	Context("when a tree is printed in prefix", func() {
+		It("should put operators first", func() {
+			Expect(parseTree("3 + 4 * 2 - sin(x) ^ 2").Prefix()).To(Equal(Tokens{
+				NewMinus(),
+				NewPlus(),
+				NewInt(3),
+				NewMul(),
+				NewInt(4),
+				NewInt(2),
+				NewPow(),
+				NewFunc("sin"),
+				NewVar("x"),
+				NewInt(2),
+			}))
+		})
+	})
+
+	Context("when prefix is parsed", func() {
+		It("should read printed prefix back", func() {
+			expr := NewNode(NewMul(), parseTree("(a[1] - b) / c ^ 2"), NewLeaf(NewInt(-3)))
+
+			parsed, err := ParsePrefixString(fmt.Sprint(expr.Prefix()))
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(parsed.Equal(expr)).To(BeTrue())
+
+			parsed, err = ParsePrefixString("- 3 x")
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(parsed.String()).To(Equal("3 - x"))
+		})
+
+		It("should check the operands", func() {
+			_, err := ParsePrefixString("* + x y")
+			Expect(err).To(Equal(&ParseError{Pos: 0, Msg: "missing operand for operator: *"}))
+
+			_, err = ParsePrefixString("+ x y z")
+			Expect(err).To(Equal(&ParseError{Pos: 6, Msg: "missing operator: 2 operands left"}))
+
+			_, err = ParsePrefixString("sin")
+			Expect(err).To(Equal(&ParseError{Pos: 0, Msg: "missing argument for function: sin"}))
+
+			_, err = ParsePrefixString("+ ( x y")
+			Expect(err).To(Equal(&ParseError{Pos: 2, Msg: "unexpected bracket in prefix"}))
+		})
+
+		It("should read statements as printed", func() {
+			stmts, err := ParsePrefixStatements(strings.NewReader("a := [+ x 1]\n- a 3 = y\n+ x; sin -2\n"))
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(stmts).To(HaveLen(4))
+
+			Expect(stmts[0].Name).To(Equal("a"))
+			Expect(stmts[0].Expr.String()).To(Equal("x + 1"))
+			Expect(stmts[1].Expr.String()).To(Equal("a - 3"))
+			Expect(stmts[1].Rhs.String()).To(Equal("y"))
+			Expect(stmts[2].Err).To(Equal(&ParseError{Pos: 23, Msg: "missing operand for operator: +"}))
+			Expect(stmts[3].Expr.String()).To(Equal("sin ( -2 )"))
+			Expect(stmts[3].Line).To(Equal(3))
+		})
+	})
+})
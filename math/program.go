@@ -0,0 +1,237 @@
+// Copyright (c) 2017 Przemysław Dobrowolski
+//
+// This file is part of the math-mod, a package for symbolic manipulation
+// of large algebraic expressions.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package math
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Program holds the statements of an input together with the definitions
+// of the files it includes. Statements keeps those of the main input only,
+// includes aside; Defs maps every assigned name to its expression.
+type Program struct {
+	Statements []Statement
+	Defs       map[string]*Expr
+
+	sites    map[string]string
+	resolved map[string]*Expr
+}
+
+type programLoader struct {
+	parser *Parser
+	prog   *Program
+	// files being loaded, from the main one, and all files loaded
+	stack  []string
+	loaded map[string]bool
+}
+
+// LoadProgram reads statements from the reader and the files it includes,
+// each at most once. Include paths are relative to the directory of the
+// including file; the reader is read from the file name, which is empty for
+// standard input. Syntax errors of the main input are left in its
+// statements, while those of included files, missing files, include cycles
+// and redefinitions fail the load.
+func LoadProgram(reader io.Reader, name string) (*Program, error) {
+	return new(Parser).LoadProgram(reader, name)
+}
+
+func LoadProgramString(src string) (*Program, error) {
+	return LoadProgram(strings.NewReader(src), "")
+}
+
+func (parser *Parser) LoadProgram(reader io.Reader, name string) (*Program, error) {
+	loader := &programLoader{
+		parser: parser,
+		prog: &Program{
+			Defs:     make(map[string]*Expr),
+			sites:    make(map[string]string),
+			resolved: make(map[string]*Expr),
+		},
+		loaded: make(map[string]bool),
+	}
+
+	if name != "" {
+		path, err := filepath.Abs(name)
+
+		if err != nil {
+			return nil, err
+		}
+
+		loader.stack = append(loader.stack, path)
+		loader.loaded[path] = true
+	}
+
+	if err := loader.load(reader, name, true); err != nil {
+		return nil, err
+	}
+
+	return loader.prog, nil
+}
+
+func (loader *programLoader) load(reader io.Reader, name string, main bool) error {
+	stmts, err := loader.parser.ParseStatements(reader)
+
+	if err != nil {
+		return err
+	}
+
+	for _, stmt := range stmts {
+		site := fmt.Sprintf("line %d", stmt.Line)
+		if name != "" {
+			site = fmt.Sprintf("%v:%d", name, stmt.Line)
+		}
+
+		switch {
+		case stmt.Err != nil:
+			if !main {
+				return fmt.Errorf("%v: %v", site, stmt.Err)
+			}
+
+		case stmt.Include != "":
+			if err := loader.include(stmt.Include, name); err != nil {
+				return fmt.Errorf("%v: %v", site, err)
+			}
+			continue
+
+		case stmt.Name != "":
+			if previous, ok := loader.prog.sites[stmt.Name]; ok {
+				return fmt.Errorf("%v: redefinition of %v, previously defined at %v", site, stmt.Name, previous)
+			}
+
+			loader.prog.Defs[stmt.Name] = stmt.Expr
+			loader.prog.sites[stmt.Name] = site
+		}
+
+		if main {
+			loader.prog.Statements = append(loader.prog.Statements, stmt)
+		}
+	}
+
+	return nil
+}
+
+// include loads the file at the path relative to the including file.
+func (loader *programLoader) include(path, from string) error {
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(filepath.Dir(from), path)
+	}
+
+	abs, err := filepath.Abs(path)
+
+	if err != nil {
+		return err
+	}
+
+	for i, loading := range loader.stack {
+		if loading == abs {
+			cycle := append(append([]string(nil), loader.stack[i:]...), abs)
+			return fmt.Errorf("include cycle: %v", strings.Join(cycle, " -> "))
+		}
+	}
+
+	if loader.loaded[abs] {
+		return nil
+	}
+
+	file, err := os.Open(path)
+
+	if err != nil {
+		return fmt.Errorf("failed to include file: %v", path)
+	}
+
+	defer file.Close()
+
+	loader.stack = append(loader.stack, abs)
+	loader.loaded[abs] = true
+
+	err = loader.load(file, path, false)
+
+	loader.stack = loader.stack[:len(loader.stack)-1]
+	return err
+}
+
+// Resolve replaces the defined names in the expression by their
+// definitions, recursively, failing on definitions which refer to
+// themselves.
+func (prog *Program) Resolve(expr *Expr) (*Expr, error) {
+	values := make(map[string]*Expr)
+
+	for _, name := range prog.defined(expr) {
+		value, err := prog.resolve(name, nil)
+
+		if err != nil {
+			return nil, err
+		}
+
+		values[name] = value
+	}
+
+	return expr.Subst(values), nil
+}
+
+func (prog *Program) resolve(name string, visiting []string) (*Expr, error) {
+	if value, ok := prog.resolved[name]; ok {
+		return value, nil
+	}
+
+	for i, other := range visiting {
+		if other == name {
+			cycle := append(append([]string(nil), visiting[i:]...), name)
+			return nil, fmt.Errorf("definition cycle: %v", strings.Join(cycle, " -> "))
+		}
+	}
+
+	visiting = append(visiting, name)
+	values := make(map[string]*Expr)
+
+	for _, other := range prog.defined(prog.Defs[name]) {
+		value, err := prog.resolve(other, visiting)
+
+		if err != nil {
+			return nil, err
+		}
+
+		values[other] = value
+	}
+
+	value := prog.Defs[name].Subst(values)
+	prog.resolved[name] = value
+	return value, nil
+}
+
+// defined returns the sorted names of the expression which are defined.
+func (prog *Program) defined(expr *Expr) (names []string) {
+	for name := range expr.Vars() {
+		if _, ok := prog.Defs[name]; ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return
+}
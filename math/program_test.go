@@ -0,0 +1,111 @@
+// Copyright (c) 2017 Przemysław Dobrowolski
+//
+// This file is part of the math-mod, a package for symbolic manipulation
+// of large algebraic expressions.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package math
+
+import (
+	"os"
+	"path/filepath"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Program Object", func() {
+	var dir string
+
+	BeforeEach(func() {
+		var err error
+		dir, err = os.MkdirTemp("", "program")
+		Expect(err).ShouldNot(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(dir)
+	})
+
+	write := func(name, src string) string {
+		path := filepath.Join(dir, name)
+		Expect(os.MkdirAll(filepath.Dir(path), 0755)).To(Succeed())
+		Expect(os.WriteFile(path, []byte(src), 0644)).To(Succeed())
+		return path
+	}
+
+	load := func(path string) (*Program, error) {
+		file, err := os.Open(path)
+		Expect(err).ShouldNot(HaveOccurred())
+		defer file.Close()
+		return LoadProgram(file, path)
+	}
+
+	Context("when files are included", func() {
+		It("should load their definitions", func() {
+			write("lib/defs.mm", "# shared definitions\nf := g^2\ninclude \"more.mm\"\nf + 1\n")
+			write("lib/more.mm", "g := x + y\n")
+			main := write("main.mm", "include \"lib/defs.mm\"\ninclude \"lib/more.mm\"\nh := f - 1\nh = 0\n")
+
+			prog, err := load(main)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(prog.Statements).To(HaveLen(2))
+			Expect(prog.Defs).To(HaveLen(3))
+
+			lhs, err := prog.Resolve(prog.Statements[1].Expr)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(lhs.String()).To(Equal("( x + y ) ^ 2 - 1"))
+		})
+
+		It("should report include cycles", func() {
+			write("a.mm", "include \"b.mm\"\n")
+			write("b.mm", "x := 1\ninclude \"a.mm\"\n")
+
+			_, err := load(filepath.Join(dir, "a.mm"))
+			Expect(err).To(MatchError(filepath.Join(dir, "a.mm") + ":1: " + filepath.Join(dir, "b.mm") + ":2: include cycle: " +
+				filepath.Join(dir, "a.mm") + " -> " + filepath.Join(dir, "b.mm") + " -> " + filepath.Join(dir, "a.mm")))
+		})
+
+		It("should report errors of included files", func() {
+			write("bad.mm", "x := (\n")
+			main := write("main.mm", "include \"bad.mm\"")
+			_, err := load(main)
+			Expect(err).To(MatchError(main + ":1: " + filepath.Join(dir, "bad.mm") + ":1: position 7: unexpected end of input"))
+
+			_, err = load(write("main.mm", "include \"missing.mm\""))
+			Expect(err).To(MatchError(ContainSubstring("failed to include file")))
+		})
+	})
+
+	Context("when definitions are resolved", func() {
+		It("should report redefinitions", func() {
+			_, err := LoadProgramString("f := 1\nf := 2")
+			Expect(err).To(MatchError("line 2: redefinition of f, previously defined at line 1"))
+		})
+
+		It("should report definition cycles", func() {
+			prog, err := LoadProgramString("f := g + 1\ng := 2 f\nf")
+			Expect(err).ShouldNot(HaveOccurred())
+
+			_, err = prog.Resolve(prog.Statements[2].Expr)
+			Expect(err).To(MatchError("definition cycle: f -> g -> f"))
+		})
+	})
+})
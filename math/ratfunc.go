@@ -0,0 +1,305 @@
+// Copyright (c) 2017 Przemysław Dobrowolski
+//
+// This file is part of the math-mod, a package for symbolic manipulation
+// of large algebraic expressions.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package math
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+)
+
+// RationalFunction is a quotient of polynomials with rational coefficients.
+// Functions returned by this package are normalised: the numerator and the
+// denominator have no common factor, their coefficients are coprime integers
+// and the leading coefficient of the denominator is positive.
+type RationalFunction struct {
+	Num, Den Poly
+}
+
+// NewRationalFunction returns the normalised quotient of the polynomials.
+func NewRationalFunction(num, den Poly) (RationalFunction, error) {
+	if den.IsZero() {
+		return RationalFunction{}, fmt.Errorf("division by zero")
+	}
+
+	num, err := num.To(Rationals)
+
+	if err != nil {
+		return RationalFunction{}, err
+	}
+
+	den, err = den.To(Rationals)
+
+	if err != nil {
+		return RationalFunction{}, err
+	}
+
+	return RationalFunction{Num: num, Den: den}.normalize(nil)
+}
+
+func newRationalPoly(poly Poly) RationalFunction {
+	return RationalFunction{Num: poly, Den: newPolyConst(Rationals, big.NewRat(1, 1))}
+}
+
+// normalize cancels the common factors of the function within the limits
+// of the guard; their gcd takes most of the time of arithmetic.
+func (f RationalFunction) normalize(g *guard) (RationalFunction, error) {
+	if f.Num.IsZero() {
+		return newRationalPoly(f.Num), nil
+	}
+
+	gcd, err := gcdField(f.Num, f.Den, g)
+
+	if err != nil {
+		return RationalFunction{}, err
+	}
+
+	num, _, err := f.Num.quoExact(gcd, g)
+
+	if err != nil {
+		return RationalFunction{}, err
+	}
+
+	den, _, err := f.Den.quoExact(gcd, g)
+
+	if err != nil {
+		return RationalFunction{}, err
+	}
+
+	scale := integerScale(num, den)
+
+	if den.Terms()[0].Coeff.Sign() < 0 {
+		scale.Neg(scale)
+	}
+
+	return RationalFunction{Num: num.scale(scale), Den: den.scale(scale)}, nil
+}
+
+// IsPoly reports whether the denominator is 1.
+func (f RationalFunction) IsPoly() bool {
+	value, ok := f.Den.Const()
+	return ok && value.Cmp(big.NewRat(1, 1)) == 0
+}
+
+func (f RationalFunction) Add(other RationalFunction) RationalFunction {
+	result, _ := f.add(other, nil)
+	return result
+}
+
+func (f RationalFunction) add(other RationalFunction, g *guard) (RationalFunction, error) {
+	if f.Den.Equal(other.Den) {
+		num, err := f.Num.add(other.Num, g)
+		if err != nil {
+			return RationalFunction{}, err
+		}
+		return RationalFunction{Num: num, Den: f.Den}.normalize(g)
+	}
+
+	left, err := f.Num.mul(other.Den, g)
+	if err != nil {
+		return RationalFunction{}, err
+	}
+
+	right, err := other.Num.mul(f.Den, g)
+	if err != nil {
+		return RationalFunction{}, err
+	}
+
+	num, err := left.add(right, g)
+	if err != nil {
+		return RationalFunction{}, err
+	}
+
+	den, err := f.Den.mul(other.Den, g)
+	if err != nil {
+		return RationalFunction{}, err
+	}
+
+	return RationalFunction{Num: num, Den: den}.normalize(g)
+}
+
+func (f RationalFunction) Neg() RationalFunction {
+	return RationalFunction{Num: f.Num.Neg(), Den: f.Den}
+}
+
+func (f RationalFunction) Sub(other RationalFunction) RationalFunction {
+	return f.Add(other.Neg())
+}
+
+func (f RationalFunction) Mul(other RationalFunction) RationalFunction {
+	result, _ := f.mul(other, nil)
+	return result
+}
+
+func (f RationalFunction) mul(other RationalFunction, g *guard) (RationalFunction, error) {
+	num, err := f.Num.mul(other.Num, g)
+	if err != nil {
+		return RationalFunction{}, err
+	}
+
+	den, err := f.Den.mul(other.Den, g)
+	if err != nil {
+		return RationalFunction{}, err
+	}
+
+	return RationalFunction{Num: num, Den: den}.normalize(g)
+}
+
+// Inv returns the reciprocal of the function; it fails for zero.
+func (f RationalFunction) Inv() (RationalFunction, error) {
+	return f.inv(nil)
+}
+
+func (f RationalFunction) inv(g *guard) (RationalFunction, error) {
+	if f.Num.IsZero() {
+		return RationalFunction{}, fmt.Errorf("division by zero")
+	}
+	return RationalFunction{Num: f.Den, Den: f.Num}.normalize(g)
+}
+
+func (f RationalFunction) Div(other RationalFunction) (RationalFunction, error) {
+	inv, err := other.Inv()
+	if err != nil {
+		return RationalFunction{}, err
+	}
+	return f.Mul(inv), nil
+}
+
+// Pow raises the function to an integer power, which may be negative.
+func (f RationalFunction) Pow(exp int) (RationalFunction, error) {
+	return f.pow(exp, nil)
+}
+
+func (f RationalFunction) pow(exp int, g *guard) (RationalFunction, error) {
+	if exp < 0 {
+		inv, err := f.inv(g)
+		if err != nil {
+			return RationalFunction{}, err
+		}
+		return inv.pow(-exp, g)
+	}
+
+	// powers of coprime polynomials stay coprime
+	num, err := f.Num.pow(exp, g)
+	if err != nil {
+		return RationalFunction{}, err
+	}
+
+	den, err := f.Den.pow(exp, g)
+	if err != nil {
+		return RationalFunction{}, err
+	}
+
+	return RationalFunction{Num: num, Den: den}, nil
+}
+
+// Expr converts the function to an expression tree, a quotient of expanded
+// polynomials or just the numerator if the denominator is 1.
+func (f RationalFunction) Expr() *Expr {
+	return f.ExprIn(Grlex)
+}
+
+// ExprIn works like Expr with the terms in descending monomial order.
+func (f RationalFunction) ExprIn(order Order) *Expr {
+	if f.IsPoly() {
+		return f.Num.ExprIn(order)
+	}
+	return NewNode(NewDiv(), f.Num.ExprIn(order), f.Den.ExprIn(order))
+}
+
+func (f RationalFunction) String() string {
+	return f.Expr().String()
+}
+
+// Together brings the expression to a single quotient of polynomials with
+// all common factors cancelled.
+func Together(expr *Expr) (RationalFunction, error) {
+	return together(expr, nil, 1)
+}
+
+// TogetherContext works like Together, but gives up when the context is done
+// or the limits are exceeded.
+func TogetherContext(ctx context.Context, expr *Expr, limits Limits) (RationalFunction, error) {
+	return together(expr, newGuard(ctx, limits), 1)
+}
+
+func together(expr *Expr, g *guard, depth int) (RationalFunction, error) {
+	if err := g.checkDepth(depth); err != nil {
+		return RationalFunction{}, err
+	}
+
+	if err := g.step(); err != nil {
+		return RationalFunction{}, err
+	}
+
+	switch expr.Token.Kind {
+	case KindInt:
+		return newRationalPoly(newPolyConst(Rationals, new(big.Rat).SetInt(expr.Token.BigInt()))), nil
+	case KindVar:
+		return newRationalPoly(newPolyVar(Rationals, expr.Token.Value.(string))), nil
+	}
+
+	if len(expr.Args) != 2 {
+		return RationalFunction{}, fmt.Errorf("unexpected token: %v", expr.Token)
+	}
+
+	left, err := together(expr.Args[0], g, depth+1)
+	if err != nil {
+		return RationalFunction{}, err
+	}
+
+	if expr.Token.Kind == KindPow {
+		right, err := expand(expr.Args[1], Integers, g, depth+1)
+		if err != nil {
+			return RationalFunction{}, err
+		}
+		exp, ok := right.Int()
+		if !ok || !exp.IsInt64() || exp.Int64() < -(1<<31-1) || exp.Int64() > 1<<31-1 {
+			return RationalFunction{}, fmt.Errorf("exponent is not an integer: %v", expr.Args[1])
+		}
+		return left.pow(int(exp.Int64()), g)
+	}
+
+	right, err := together(expr.Args[1], g, depth+1)
+	if err != nil {
+		return RationalFunction{}, err
+	}
+
+	switch expr.Token.Kind {
+	case KindPlus:
+		return left.add(right, g)
+	case KindMinus:
+		return left.add(right.Neg(), g)
+	case KindMul:
+		return left.mul(right, g)
+	case KindDiv:
+		inv, err := right.inv(g)
+		if err != nil {
+			return RationalFunction{}, err
+		}
+		return left.mul(inv, g)
+	}
+
+	return RationalFunction{}, fmt.Errorf("unexpected token: %v", expr.Token)
+}
@@ -0,0 +1,73 @@
+// Copyright (c) 2017 Przemysław Dobrowolski
+//
+// This file is part of the math-mod, a package for symbolic manipulation
+// of large algebraic expressions.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package math
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"context"
+)
+
+func togetherString(infix string) string {
+	f, err := Together(parseTree(infix))
+	Expect(err).ShouldNot(HaveOccurred())
+	return f.String()
+}
+
+var _ = Describe("RationalFunction Object", func() {
+	Context("when a sum of fractions is brought together", func() {
+		It("should use a common denominator", func() {
+			Expect(togetherString("1 / x + 1 / y")).To(Equal("( x + y ) / ( x * y )"))
+			Expect(togetherString("x / 2 - 1 / 3")).To(Equal("( 3 * x - 2 ) / 6"))
+		})
+
+		It("should cancel common factors", func() {
+			Expect(togetherString("(x^2 - 1) / (x + 1)")).To(Equal("x - 1"))
+			Expect(togetherString("(x^2 - y^2) / (2 y - 2 x)")).To(Equal("( -1 * x - y ) / 2"))
+			Expect(togetherString("1 / (x - 1) - 1 / (x + 1)")).To(Equal("2 / ( x ^ 2 - 1 )"))
+		})
+
+		It("should accept negative exponents", func() {
+			Expect(togetherString("x^(0 - 2) y^2 + 1")).To(Equal("( x ^ 2 + y ^ 2 ) / x ^ 2"))
+		})
+	})
+
+	Context("when the expression divides by zero", func() {
+		It("should fail", func() {
+			_, err := Together(parseTree("1 / (x - x)"))
+			Expect(err).Should(HaveOccurred())
+
+			_, err = Together(parseTree("sin(x) / x"))
+			Expect(err).Should(HaveOccurred())
+		})
+	})
+
+	Context("when limits are exceeded", func() {
+		It("should report them", func() {
+			_, err := TogetherContext(context.Background(), parseTree("1 / (x + y + 1)^20"), Limits{MaxTerms: 50})
+			Expect(err).Should(BeAssignableToTypeOf(&LimitError{}))
+		})
+	})
+})
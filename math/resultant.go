@@ -0,0 +1,311 @@
+// Copyright (c) 2017 Przemysław Dobrowolski
+//
+// This file is part of the math-mod, a package for symbolic manipulation
+// of large algebraic expressions.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package math
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+)
+
+// Resultant returns the resultant of the polynomials with respect to the
+// variable, a polynomial in the other variables that vanishes exactly where
+// they have a common root. It is computed with the subresultant polynomial
+// remainder sequence, whose divisions are exact, so it works over Z as well
+// as over fields.
+func Resultant(a, b Poly, name string) (Poly, error) {
+	return resultant(a, b, name, nil)
+}
+
+// ResultantContext works like Resultant, but gives up when the context is
+// done or the limits are exceeded.
+func ResultantContext(ctx context.Context, a, b Poly, name string, limits Limits) (Poly, error) {
+	return resultant(a, b, name, newGuard(ctx, limits))
+}
+
+// resultant runs the subresultant sequence, in which lc is the leading
+// coefficient of the last remainder and h its scaled power.
+func resultant(a, b Poly, name string, g *guard) (Poly, error) {
+	ring := a.Ring()
+	b, err := b.To(ring)
+
+	if err != nil {
+		return Poly{}, err
+	}
+
+	if a.IsZero() || b.IsZero() {
+		return NewPolyRing(ring), nil
+	}
+
+	one := newPolyConst(ring, big.NewRat(1, 1))
+	sign := one
+
+	if a.DegreeIn(name) < b.DegreeIn(name) {
+		if a.DegreeIn(name)%2 == 1 && b.DegreeIn(name)%2 == 1 {
+			sign = sign.Neg()
+		}
+		a, b = b, a
+	}
+
+	lc, h := one, one
+
+	for b.DegreeIn(name) > 0 {
+		degreeA, degreeB := a.DegreeIn(name), b.DegreeIn(name)
+		delta := degreeA - degreeB
+
+		if degreeA%2 == 1 && degreeB%2 == 1 {
+			sign = sign.Neg()
+		}
+
+		_, rem, err := pseudoDivMod(a, b, name, g)
+
+		if err != nil {
+			return Poly{}, err
+		}
+
+		if rem.IsZero() {
+			return NewPolyRing(ring), nil
+		}
+
+		scale, err := h.pow(delta, g)
+
+		if err != nil {
+			return Poly{}, err
+		}
+
+		if scale, err = lc.mul(scale, g); err != nil {
+			return Poly{}, err
+		}
+
+		a = b
+		if b, err = divExactly(rem, scale, g); err != nil {
+			return Poly{}, err
+		}
+
+		lc = a.CoeffIn(name, a.DegreeIn(name))
+
+		if delta > 0 {
+			if h, err = divPowers(lc, delta, h, delta-1, g); err != nil {
+				return Poly{}, err
+			}
+		}
+	}
+
+	// b is free of the variable and the sequence ends with it
+	degreeA := a.DegreeIn(name)
+
+	if degreeA == 0 {
+		return sign, nil
+	}
+
+	h, err = divPowers(b, degreeA, h, degreeA-1, g)
+
+	if err != nil {
+		return Poly{}, err
+	}
+
+	return sign.Mul(h), nil
+}
+
+// divExactly divides polynomials known to divide each other.
+func divExactly(a, b Poly, g *guard) (Poly, error) {
+	quo, ok, err := divExact(a, b, g)
+
+	if err != nil {
+		return Poly{}, err
+	}
+
+	if !ok {
+		return Poly{}, fmt.Errorf("inexact division in %v", a.Ring())
+	}
+	return quo, nil
+}
+
+// divPowers returns a^m/b^n for powers known to divide each other.
+func divPowers(a Poly, m int, b Poly, n int, g *guard) (Poly, error) {
+	num, err := a.pow(m, g)
+
+	if err != nil {
+		return Poly{}, err
+	}
+
+	den, err := b.pow(n, g)
+
+	if err != nil {
+		return Poly{}, err
+	}
+
+	return divExactly(num, den, g)
+}
+
+// Sylvester returns the Sylvester matrix of the polynomials with respect to
+// the variable: the shifted coefficients of a, one row per degree of b,
+// followed by those of b, one row per degree of a.
+func Sylvester(a, b Poly, name string) [][]Poly {
+	degreeA, degreeB := a.DegreeIn(name), b.DegreeIn(name)
+	size := degreeA + degreeB
+	matrix := make([][]Poly, size)
+
+	for i := range matrix {
+		poly, degree, shift := a, degreeA, i
+
+		if i >= degreeB {
+			poly, degree, shift = b, degreeB, i-degreeB
+		}
+
+		matrix[i] = make([]Poly, size)
+
+		for j := range matrix[i] {
+			if exp := degree - (j - shift); j >= shift && exp >= 0 {
+				matrix[i][j] = poly.CoeffIn(name, exp)
+			} else {
+				matrix[i][j] = NewPolyRing(a.Ring())
+			}
+		}
+	}
+	return matrix
+}
+
+// SylvesterResultant returns the resultant computed as the determinant of
+// the Sylvester matrix with fraction-free Gaussian elimination. It is
+// slower than Resultant and is meant for cross-checking.
+func SylvesterResultant(a, b Poly, name string) (Poly, error) {
+	return sylvesterResultant(a, b, name, nil)
+}
+
+// SylvesterResultantContext works like SylvesterResultant, but gives up
+// when the context is done or the limits are exceeded.
+func SylvesterResultantContext(ctx context.Context, a, b Poly, name string, limits Limits) (Poly, error) {
+	return sylvesterResultant(a, b, name, newGuard(ctx, limits))
+}
+
+func sylvesterResultant(a, b Poly, name string, g *guard) (Poly, error) {
+	b, err := b.To(a.Ring())
+
+	if err != nil {
+		return Poly{}, err
+	}
+
+	if a.IsZero() || b.IsZero() {
+		return NewPolyRing(a.Ring()), nil
+	}
+
+	return determinant(Sylvester(a, b, name), a.Ring(), g)
+}
+
+// determinant computes the determinant with Bareiss' algorithm, in which
+// every division is exact.
+func determinant(matrix [][]Poly, ring Ring, g *guard) (Poly, error) {
+	one := newPolyConst(ring, big.NewRat(1, 1))
+	size := len(matrix)
+
+	if size == 0 {
+		return one, nil
+	}
+
+	sign, prev := false, one
+
+	for k := 0; k < size-1; k++ {
+		if matrix[k][k].IsZero() {
+			pivot := k + 1
+			for pivot < size && matrix[pivot][k].IsZero() {
+				pivot++
+			}
+			if pivot == size {
+				return NewPolyRing(ring), nil
+			}
+			matrix[k], matrix[pivot] = matrix[pivot], matrix[k]
+			sign = !sign
+		}
+
+		for i := k + 1; i < size; i++ {
+			for j := k + 1; j < size; j++ {
+				if err := g.step(); err != nil {
+					return Poly{}, err
+				}
+
+				value, err := matrix[i][j].mul(matrix[k][k], g)
+
+				if err != nil {
+					return Poly{}, err
+				}
+
+				other, err := matrix[i][k].mul(matrix[k][j], g)
+
+				if err != nil {
+					return Poly{}, err
+				}
+
+				if value, err = value.add(other.Neg(), g); err != nil {
+					return Poly{}, err
+				}
+
+				if matrix[i][j], err = divExactly(value, prev, g); err != nil {
+					return Poly{}, err
+				}
+			}
+		}
+		prev = matrix[k][k]
+	}
+
+	if sign {
+		return matrix[size-1][size-1].Neg(), nil
+	}
+	return matrix[size-1][size-1], nil
+}
+
+// Discriminant returns the discriminant of the polynomial with respect to
+// the variable, which vanishes exactly where it has a multiple root.
+func Discriminant(poly Poly, name string) (Poly, error) {
+	return discriminant(poly, name, nil)
+}
+
+// DiscriminantContext works like Discriminant, but gives up when the context
+// is done or the limits are exceeded.
+func DiscriminantContext(ctx context.Context, poly Poly, name string, limits Limits) (Poly, error) {
+	return discriminant(poly, name, newGuard(ctx, limits))
+}
+
+func discriminant(poly Poly, name string, g *guard) (Poly, error) {
+	degree := poly.DegreeIn(name)
+
+	if degree < 1 {
+		return Poly{}, fmt.Errorf("polynomial has degree %d in %v", degree, name)
+	}
+
+	res, err := resultant(poly, poly.Diff(name), name, g)
+
+	if err != nil {
+		return Poly{}, err
+	}
+
+	if res, err = divExactly(res, poly.CoeffIn(name, degree), g); err != nil {
+		return Poly{}, err
+	}
+
+	if degree*(degree-1)/2%2 == 1 {
+		res = res.Neg()
+	}
+	return res, nil
+}
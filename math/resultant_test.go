@@ -0,0 +1,107 @@
+// Copyright (c) 2017 Przemysław Dobrowolski
+//
+// This file is part of the math-mod, a package for symbolic manipulation
+// of large algebraic expressions.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package math
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"context"
+)
+
+var _ = Describe("Resultant Object", func() {
+	Context("when a variable is eliminated", func() {
+		It("should give the condition for a common root", func() {
+			res, err := Resultant(expandString("x^2 - y"), expandString("x - z"), "x")
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(res.String()).To(Equal("z ^ 2 - y"))
+
+			res, err = Resultant(expandString("x - z"), expandString("x^2 - y"), "x")
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(res.String()).To(Equal("z ^ 2 - y"))
+
+			res, err = Resultant(expandString("(x - 1)(x + 2)"), expandString("(x - 1) y + 3"), "x")
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(res.String()).To(Equal("-9 * y + 9"))
+		})
+
+		It("should match the Sylvester determinant", func() {
+			pairs := [][2]string{
+				{"x^4 y + 3 x^2 - y^2 x + 7", "2 x^3 - x y + y^3 - 1"},
+				{"x^3 + x + y", "x^3 - y x^2 + 2"},
+				{"3 x^5 - x + z", "x^2 y - z^2"},
+				{"x^2 + 1", "5"},
+			}
+
+			for _, pair := range pairs {
+				a, b := expandString(pair[0]), expandString(pair[1])
+
+				res, err := Resultant(a, b, "x")
+				Expect(err).ShouldNot(HaveOccurred())
+				det, err := SylvesterResultant(a, b, "x")
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(res.String()).To(Equal(det.String()))
+			}
+		})
+
+		It("should vanish for a common factor", func() {
+			res, err := Resultant(expandString("(x - y)(x + 1)"), expandString("(x - y)(x^2 + 3)"), "x")
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(res.IsZero()).To(BeTrue())
+		})
+	})
+
+	Context("when a discriminant is computed", func() {
+		It("should use the classical formulas", func() {
+			disc, err := Discriminant(expandString("a x^2 + b x + c"), "x")
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(disc.String()).To(Equal("-4 * a * c + b ^ 2"))
+
+			disc, err = Discriminant(expandString("x^3 + p x + q"), "x")
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(disc.String()).To(Equal("-4 * p ^ 3 - 27 * q ^ 2"))
+
+			_, err = Discriminant(expandString("y"), "x")
+			Expect(err).Should(HaveOccurred())
+		})
+	})
+	Context("when the limits are exceeded", func() {
+		It("should give up", func() {
+			a, b := expandString("(x + y + z + 1)^4"), expandString("(x - y - z)^3 + 1")
+
+			res, err := ResultantContext(context.Background(), a, b, "x", Limits{})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(res.IsZero()).To(BeFalse())
+
+			_, err = ResultantContext(context.Background(), a, b, "x", Limits{MaxTerms: 20})
+			Expect(err).To(MatchError("terms limit exceeded: 20"))
+
+			_, err = SylvesterResultantContext(context.Background(), a, b, "x", Limits{MaxTerms: 20})
+			Expect(err).To(MatchError("terms limit exceeded: 20"))
+
+			_, err = DiscriminantContext(context.Background(), a, "x", Limits{MaxTerms: 20})
+			Expect(err).To(MatchError("terms limit exceeded: 20"))
+		})
+	})
+})
@@ -0,0 +1,443 @@
+// Copyright (c) 2017 Przemysław Dobrowolski
+//
+// This file is part of the math-mod, a package for symbolic manipulation
+// of large algebraic expressions.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package math
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Rule rewrites expressions matching Lhs into Rhs. A name written with a
+// leading question mark, like ?a, is a wildcard that matches any
+// subexpression, other names match only themselves; a wildcard occurring
+// more than once must match equal subexpressions. Sums and products are
+// matched modulo commutativity and associativity, and the last wildcard
+// operand of such a pattern takes all operands left by the others.
+type Rule struct {
+	Lhs *Expr
+	Rhs *Expr
+}
+
+func ParseRule(rule string) (Rule, error) {
+	sides := strings.Split(rule, "->")
+
+	if len(sides) != 2 {
+		return Rule{}, fmt.Errorf("rule must have the form lhs -> rhs: %v", rule)
+	}
+
+	lhs, err := parsePattern(sides[0])
+
+	if err != nil {
+		return Rule{}, err
+	}
+
+	rhs, err := parsePattern(sides[1])
+
+	if err != nil {
+		return Rule{}, err
+	}
+
+	wildcards := lhs.Vars()
+
+	for name := range rhs.Vars() {
+		if isWildcard(name) && !wildcards[name] {
+			return Rule{}, fmt.Errorf("unbound wildcard in right-hand side: %v", name)
+		}
+	}
+
+	return Rule{Lhs: lhs, Rhs: rhs}, nil
+}
+
+// parsePattern parses a side of a rule. Wildcards are kept as variables
+// whose names start with the question mark, which no parsed name can.
+func parsePattern(src string) (*Expr, error) {
+	// blank out the question marks to keep positions
+	data := []byte(strings.ReplaceAll(src, "?", " "))
+	tokens, positions, err := ParseInfixPositions(bytes.NewReader(data))
+
+	if err != nil {
+		return nil, err
+	}
+
+	wildcards := make(map[string]*Expr)
+	names := make(map[string]bool)
+	marked := make(map[int]bool)
+
+	for i, token := range tokens {
+		if token.Kind != KindVar {
+			continue
+		}
+
+		name, pos := token.Value.(string), positions[i]
+
+		if pos > 0 && src[pos-1] == '?' {
+			wildcards[name] = NewLeaf(NewVar("?" + name))
+			marked[pos-1] = true
+		} else {
+			names[name] = true
+		}
+
+		if wildcards[name] != nil && names[name] {
+			return nil, &ParseError{Pos: pos, Msg: fmt.Sprintf("name is also used as a wildcard: %v", name)}
+		}
+	}
+
+	for i := range data {
+		if src[i] == '?' && !marked[i] {
+			return nil, &ParseError{Pos: i, Msg: "wildcard must be followed by a name"}
+		}
+	}
+
+	expr, err := Parse(bytes.NewReader(data))
+
+	if err != nil {
+		return nil, err
+	}
+
+	return expr.Subst(wildcards), nil
+}
+
+func isWildcard(name string) bool {
+	return strings.HasPrefix(name, "?")
+}
+
+// ParseRules reads one rule per line, skipping blank lines.
+func ParseRules(reader io.Reader) (rules []Rule, err error) {
+	scanner := bufio.NewScanner(reader)
+
+	for line := 1; scanner.Scan(); line++ {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+
+		rule, err := ParseRule(scanner.Text())
+
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", line, err)
+		}
+
+		rules = append(rules, rule)
+	}
+
+	return rules, scanner.Err()
+}
+
+// Vars returns the set of variable names occurring in the tree.
+func (expr *Expr) Vars() map[string]bool {
+	vars := make(map[string]bool)
+	expr.collectVars(vars)
+	return vars
+}
+
+func (expr *Expr) collectVars(vars map[string]bool) {
+	if expr.Token.Kind == KindVar {
+		vars[expr.Token.Value.(string)] = true
+	}
+	for _, arg := range expr.Args {
+		arg.collectVars(vars)
+	}
+}
+
+// Rewrite applies the rules until none of them matches anywhere in the tree.
+// Rules are tried in order, outermost subexpressions first. It fails when
+// more than limit rewriting steps would be needed, returning the expression
+// reached so far.
+func Rewrite(expr *Expr, rules []Rule, limit int) (*Expr, int, error) {
+	return rewrite(expr, rules, limit, nil)
+}
+
+// RewriteContext works like Rewrite, but gives up when the context is done or
+// a rewritten tree exceeds the depth limit. The limits of polynomials,
+// MaxTerms and MaxMemory, do not apply to trees.
+func RewriteContext(ctx context.Context, expr *Expr, rules []Rule, limit int, limits Limits) (*Expr, int, error) {
+	return rewrite(expr, rules, limit, newGuard(ctx, limits))
+}
+
+func rewrite(expr *Expr, rules []Rule, limit int, g *guard) (*Expr, int, error) {
+	for steps := 0; ; steps++ {
+		result, ok := rewriteStep(expr, rules)
+
+		if !ok {
+			return expr, steps, nil
+		}
+
+		if steps == limit {
+			return expr, steps, fmt.Errorf("step limit exceeded: %d", limit)
+		}
+
+		if g != nil {
+			if err := g.ctx.Err(); err != nil {
+				return expr, steps, err
+			}
+
+			if err := g.checkDepth(result.Depth()); err != nil {
+				return expr, steps, err
+			}
+		}
+
+		expr = result
+	}
+}
+
+func rewriteStep(expr *Expr, rules []Rule) (*Expr, bool) {
+	for _, rule := range rules {
+		if result, ok := rule.Apply(expr); ok {
+			return result, true
+		}
+	}
+
+	for i, arg := range expr.Args {
+		if result, ok := rewriteStep(arg, rules); ok {
+			args := append([]*Expr(nil), expr.Args...)
+			args[i] = result
+			return NewNode(expr.Token, args...), true
+		}
+	}
+
+	return nil, false
+}
+
+// Apply rewrites the expression if its root matches the rule. A sum or
+// product pattern may match only some of the operands, in which case the
+// remaining operands are kept next to the replacement.
+func (rule Rule) Apply(expr *Expr) (*Expr, bool) {
+	if isAC(rule.Lhs.Token.Kind) && expr.Token.Kind == rule.Lhs.Token.Kind {
+		subjects := expr.operands(expr.Token.Kind)
+		bindings, used, ok := matchOperands(expr.Token, rule.Lhs.operands(expr.Token.Kind), subjects, make(map[string]*Expr), true)
+
+		if !ok {
+			return nil, false
+		}
+
+		var result []*Expr
+		replaced := false
+		for i, subject := range subjects {
+			switch {
+			case !used[i]:
+				result = append(result, subject)
+			case !replaced:
+				result = append(result, rule.Rhs.Subst(bindings))
+				replaced = true
+			}
+		}
+		return fold(expr.Token, result), true
+	}
+
+	bindings, ok := match(rule.Lhs, expr, make(map[string]*Expr))
+
+	if !ok {
+		return nil, false
+	}
+
+	return rule.Rhs.Subst(bindings), true
+}
+
+func isAC(kind Kind) bool {
+	return kind == KindPlus || kind == KindMul
+}
+
+// operands flattens a chain of the associative operator into its operands.
+func (expr *Expr) operands(kind Kind) []*Expr {
+	if expr.Token.Kind != kind {
+		return []*Expr{expr}
+	}
+
+	var operands []*Expr
+	for _, arg := range expr.Args {
+		operands = append(operands, arg.operands(kind)...)
+	}
+	return operands
+}
+
+func fold(token Token, operands []*Expr) *Expr {
+	result := operands[0]
+
+	for _, operand := range operands[1:] {
+		result = NewNode(token, result, operand)
+	}
+	return result
+}
+
+// bind returns a copy of bindings extended by name, so that failed branches
+// of the search leave the caller's bindings intact.
+func bind(bindings map[string]*Expr, name string, expr *Expr) map[string]*Expr {
+	result := make(map[string]*Expr, len(bindings)+1)
+
+	for key, value := range bindings {
+		result[key] = value
+	}
+	result[name] = expr
+	return result
+}
+
+func match(pattern, subject *Expr, bindings map[string]*Expr) (map[string]*Expr, bool) {
+	switch pattern.Token.Kind {
+	case KindVar:
+		name := pattern.Token.Value.(string)
+		if !isWildcard(name) {
+			return bindings, pattern.Equal(subject)
+		}
+		if bound, ok := bindings[name]; ok {
+			return bindings, equalAC(bound, subject)
+		}
+		return bind(bindings, name, subject), true
+	case KindInt:
+		return bindings, pattern.Equal(subject)
+	}
+
+	if pattern.Token.Kind != subject.Token.Kind {
+		return nil, false
+	}
+
+	if isAC(pattern.Token.Kind) {
+		bindings, _, ok := matchOperands(pattern.Token, pattern.operands(pattern.Token.Kind), subject.operands(subject.Token.Kind), bindings, false)
+		return bindings, ok
+	}
+
+	if pattern.Token.Kind == KindFunc && pattern.Token.Value.(string) != subject.Token.Value.(string) {
+		return nil, false
+	}
+
+	for i, arg := range pattern.Args {
+		var ok bool
+		if bindings, ok = match(arg, subject.Args[i], bindings); !ok {
+			return nil, false
+		}
+	}
+	return bindings, true
+}
+
+// equalAC reports whether both trees are equal up to the order and grouping
+// of operands of sums and products.
+func equalAC(a, b *Expr) bool {
+	if a.Token.Kind != b.Token.Kind {
+		return false
+	}
+
+	if !isAC(a.Token.Kind) {
+		if !NewLeaf(a.Token).Equal(NewLeaf(b.Token)) {
+			return false
+		}
+		for i, arg := range a.Args {
+			if !equalAC(arg, b.Args[i]) {
+				return false
+			}
+		}
+		return true
+	}
+
+	operandsA, operandsB := a.operands(a.Token.Kind), b.operands(b.Token.Kind)
+	if len(operandsA) != len(operandsB) {
+		return false
+	}
+
+	used := make([]bool, len(operandsB))
+next:
+	for _, operandA := range operandsA {
+		for j, operandB := range operandsB {
+			if !used[j] && equalAC(operandA, operandB) {
+				used[j] = true
+				continue next
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// matchOperands matches the operands of a sum or product pattern against
+// the operands of the subject. Wildcard operands are matched last, so that
+// the last one can take all operands left by the rest of the pattern.
+func matchOperands(token Token, patterns, subjects []*Expr, bindings map[string]*Expr, partial bool) (map[string]*Expr, []bool, bool) {
+	var ordered, wildcards []*Expr
+
+	for _, pattern := range patterns {
+		if pattern.Token.Kind == KindVar && isWildcard(pattern.Token.Value.(string)) {
+			wildcards = append(wildcards, pattern)
+		} else {
+			ordered = append(ordered, pattern)
+		}
+	}
+
+	return matchAC(token, append(ordered, wildcards...), subjects, bindings, make([]bool, len(subjects)), partial)
+}
+
+// matchAC assigns every pattern to a distinct unused subject, backtracking
+// over the choices; the last pattern, if it is a free wildcard, takes all
+// unused subjects. Unless partial is set all subjects must be used.
+func matchAC(token Token, patterns, subjects []*Expr, bindings map[string]*Expr, used []bool, partial bool) (map[string]*Expr, []bool, bool) {
+	if len(patterns) == 0 {
+		for _, u := range used {
+			if !u && !partial {
+				return nil, nil, false
+			}
+		}
+		return bindings, used, true
+	}
+
+	if pattern := patterns[0]; pattern.Token.Kind == KindVar && isWildcard(pattern.Token.Value.(string)) {
+		name := pattern.Token.Value.(string)
+
+		// a wildcard bound to a sum or product matches its operands
+		if bound, ok := bindings[name]; ok && bound.Token.Kind == token.Kind {
+			return matchAC(token, append(bound.operands(token.Kind), patterns[1:]...), subjects, bindings, used, partial)
+		}
+
+		if _, ok := bindings[name]; !ok && len(patterns) == 1 {
+			var rest []*Expr
+			for i, subject := range subjects {
+				if !used[i] {
+					rest = append(rest, subject)
+				}
+			}
+			if len(rest) == 0 {
+				return nil, nil, false
+			}
+			for i := range used {
+				used[i] = true
+			}
+			return bind(bindings, name, fold(token, rest)), used, true
+		}
+	}
+
+	for i, subject := range subjects {
+		if used[i] {
+			continue
+		}
+
+		if result, ok := match(patterns[0], subject, bindings); ok {
+			used[i] = true
+			if result, used, ok := matchAC(token, patterns[1:], subjects, result, used, partial); ok {
+				return result, used, true
+			}
+			used[i] = false
+		}
+	}
+
+	return nil, nil, false
+}
@@ -0,0 +1,122 @@
+// Copyright (c) 2017 Przemysław Dobrowolski
+//
+// This file is part of the math-mod, a package for symbolic manipulation
+// of large algebraic expressions.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package math
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"strings"
+)
+
+func rewriteString(infix string, rules ...string) string {
+	var parsed []Rule
+
+	for _, rule := range rules {
+		r, err := ParseRule(rule)
+		Expect(err).ShouldNot(HaveOccurred())
+		parsed = append(parsed, r)
+	}
+
+	expr, _, err := Rewrite(parseTree(infix), parsed, 100)
+	Expect(err).ShouldNot(HaveOccurred())
+	return expr.String()
+}
+
+var _ = Describe("Rewrite Object", func() {
+	Context("when function calls are parsed", func() {
+		It("should not insert implicit multiplication", func() {
+			Expect(parseTree("2 sin(x) cos(y)^2").String()).To(Equal("2 * sin ( x ) * cos ( y ) ^ 2"))
+		})
+	})
+
+	Context("when a pattern matches part of a sum", func() {
+		It("should keep the other operands", func() {
+			Expect(rewriteString("x + cos(y + 1)^2 + 3 + sin(1 + y)^2", "sin(?a)^2 + cos(?a)^2 -> 1")).To(Equal("x + 1 + 3"))
+		})
+	})
+
+	Context("when a wildcard occurs twice", func() {
+		It("should require equal subexpressions", func() {
+			Expect(rewriteString("x^2 * y^3", "?x^?a * ?x^?b -> ?x^(?a + ?b)")).To(Equal("x ^ 2 * y ^ 3"))
+			Expect(rewriteString("z * x^2 * x^3", "?x^?a * ?x^?b -> ?x^(?a + ?b)")).To(Equal("z * x ^ ( 2 + 3 )"))
+		})
+	})
+
+	Context("when a rule names a variable", func() {
+		It("should match only that variable", func() {
+			Expect(rewriteString("sin(x) + sin(y)", "sin(x) -> 0")).To(Equal("0 + sin ( y )"))
+			Expect(rewriteString("x * y", "x * ?a -> sin(?a)")).To(Equal("sin ( y )"))
+		})
+	})
+
+	Context("when a wildcard is an operand of a sum", func() {
+		It("should take the remaining operands", func() {
+			Expect(rewriteString("sin(x + y + z + 1)", "sin(1 + ?rest) -> cos(?rest)")).To(Equal("cos ( x + y + z )"))
+			Expect(rewriteString("sin(x + 1)", "sin(1 + ?rest) -> cos(?rest)")).To(Equal("cos ( x )"))
+			Expect(rewriteString("sin(1)", "sin(1 + ?rest) -> cos(?rest)")).To(Equal("sin ( 1 )"))
+			Expect(rewriteString("sin(x + y) * cos(y + z + x)", "sin(?a) * cos(?a + ?b) -> ?b")).To(Equal("z"))
+		})
+	})
+
+	Context("when a wildcard is written wrongly", func() {
+		It("should fail", func() {
+			_, err := ParseRule("? x -> x")
+			Expect(err).To(MatchError("position 0: wildcard must be followed by a name"))
+			_, err = ParseRule("?x + x -> 0")
+			Expect(err).To(MatchError("position 5: name is also used as a wildcard: x"))
+		})
+	})
+
+	Context("when rules are applied to fixpoint", func() {
+		It("should apply them repeatedly", func() {
+			Expect(rewriteString("a * 1 * (b * 1)", "?x * 1 -> ?x")).To(Equal("a * b"))
+		})
+	})
+
+	Context("when the step limit is exceeded", func() {
+		It("should fail", func() {
+			rule, err := ParseRule("?x -> ?x + 0")
+			Expect(err).ShouldNot(HaveOccurred())
+
+			_, steps, err := Rewrite(parseTree("y"), []Rule{rule}, 10)
+			Expect(err).Should(HaveOccurred())
+			Expect(steps).To(Equal(10))
+		})
+	})
+
+	Context("when a rule introduces a new variable", func() {
+		It("should fail", func() {
+			_, err := ParseRule("?x -> ?y")
+			Expect(err).Should(HaveOccurred())
+		})
+	})
+
+	Context("when rules are read from a file", func() {
+		It("should report the failing line", func() {
+			_, err := ParseRules(strings.NewReader("?x * 1 -> ?x\n\n?x + \n"))
+			Expect(err).To(MatchError(ContainSubstring("line 3")))
+		})
+	})
+})
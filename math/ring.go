@@ -0,0 +1,137 @@
+// Copyright (c) 2017 Przemysław Dobrowolski
+//
+// This file is part of the math-mod, a package for symbolic manipulation
+// of large algebraic expressions.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package math
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// Ring is a domain of polynomial coefficients. Coefficients are held as
+// *big.Rat in the canonical form chosen by the ring.
+type Ring interface {
+	// Reduce returns the canonical form of the value as a new *big.Rat, or
+	// false if the value does not belong to the ring.
+	Reduce(value *big.Rat) (*big.Rat, bool)
+
+	// IsField reports whether every non-zero element has an inverse.
+	IsField() bool
+
+	String() string
+}
+
+// Integers is the ring Z.
+var Integers Ring = integers{}
+
+// Rationals is the field Q.
+var Rationals Ring = rationals{}
+
+type integers struct{}
+
+func (integers) Reduce(value *big.Rat) (*big.Rat, bool) {
+	if !value.IsInt() {
+		return nil, false
+	}
+
+	return new(big.Rat).Set(value), true
+}
+
+func (integers) IsField() bool {
+	return false
+}
+
+func (integers) String() string {
+	return "Z"
+}
+
+type rationals struct{}
+
+func (rationals) Reduce(value *big.Rat) (*big.Rat, bool) {
+	return new(big.Rat).Set(value), true
+}
+
+func (rationals) IsField() bool {
+	return true
+}
+
+func (rationals) String() string {
+	return "Q"
+}
+
+// Modular is the field Z/pZ for a word-sized prime p. Elements are kept as
+// integers in [0, p).
+type Modular struct {
+	p *big.Int
+}
+
+func NewModular(p uint64) (Modular, error) {
+	prime := new(big.Int).SetUint64(p)
+
+	if !prime.ProbablyPrime(20) {
+		return Modular{}, fmt.Errorf("modulus is not a prime: %d", p)
+	}
+
+	return Modular{p: prime}, nil
+}
+
+// Reduce maps a/b to a*b^-1 mod p. It fails if p divides b.
+func (ring Modular) Reduce(value *big.Rat) (*big.Rat, bool) {
+	num := new(big.Int).Mod(value.Num(), ring.p)
+
+	if !value.IsInt() {
+		inv := new(big.Int).ModInverse(value.Denom(), ring.p)
+
+		if inv == nil {
+			return nil, false
+		}
+
+		num.Mod(num.Mul(num, inv), ring.p)
+	}
+
+	return new(big.Rat).SetInt(num), true
+}
+
+func (ring Modular) IsField() bool {
+	return true
+}
+
+func (ring Modular) Modulus() uint64 {
+	return ring.p.Uint64()
+}
+
+func (ring Modular) String() string {
+	return fmt.Sprintf("Z/%vZ", ring.p)
+}
+
+// inverse returns the multiplicative inverse of a non-zero element of a
+// field.
+func inverse(ring Ring, value *big.Rat) *big.Rat {
+	inv, ok := ring.Reduce(new(big.Rat).Inv(value))
+
+	if !ok {
+		panic("element is not invertible")
+	}
+
+	return inv
+}
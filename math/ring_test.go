@@ -0,0 +1,149 @@
+// Copyright (c) 2017 Przemysław Dobrowolski
+//
+// This file is part of the math-mod, a package for symbolic manipulation
+// of large algebraic expressions.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package math
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"context"
+	"math/big"
+)
+
+func expandRing(infix string, ring Ring) Poly {
+	poly, err := ExpandRing(context.Background(), parseTree(infix), ring, Limits{}, 1)
+	Expect(err).ShouldNot(HaveOccurred())
+	return poly
+}
+
+var _ = Describe("Ring Object", func() {
+	var mod7 Ring
+
+	BeforeEach(func() {
+		var err error
+		mod7, err = NewModular(7)
+		Expect(err).ShouldNot(HaveOccurred())
+	})
+
+	Context("when the modulus is not a prime", func() {
+		It("should fail", func() {
+			_, err := NewModular(91)
+			Expect(err).Should(HaveOccurred())
+		})
+	})
+
+	Context("when an expression is expanded modulo p", func() {
+		It("should reduce the coefficients", func() {
+			Expect(expandRing("(x + 1)^7", mod7).String()).To(Equal("x ^ 7 + 1"))
+			Expect(expandRing("(x - 1)^2", mod7).String()).To(Equal("x ^ 2 + 5 * x + 1"))
+		})
+	})
+
+	Context("when exponents are expanded modulo p", func() {
+		It("should not reduce them", func() {
+			Expect(expandRing("x^(3 + 5)", mod7).String()).To(Equal("x ^ 8"))
+		})
+	})
+
+	Context("when an expression is evaluated in a ring", func() {
+		It("should compute exactly", func() {
+			values := map[string]*big.Rat{"x": big.NewRat(1, 2), "y": big.NewRat(3, 1)}
+
+			value, err := EvalRing(parseTree("x^3 y - x^(0 - 1)"), Rationals, values)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(value.RatString()).To(Equal("-13/8"))
+
+			value, err = EvalRing(parseTree("x^3 y - x^(0 - 1)"), mod7, values)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(value.RatString()).To(Equal("1"))
+
+			_, err = EvalRing(parseTree("x y"), Integers, values)
+			Expect(err).Should(HaveOccurred())
+		})
+	})
+
+	Context("when a huge power is evaluated within limits", func() {
+		It("should give up", func() {
+			_, err := EvalRingContext(context.Background(), parseTree("2^(10^9)"), Integers, nil, Limits{MaxMemory: 1 << 20})
+			Expect(err).To(Equal(&LimitError{Resource: "memory", Limit: 1 << 20}))
+
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+
+			_, err = EvalRingContext(ctx, parseTree("2^(10^9)"), Rationals, nil, Limits{})
+			Expect(err).To(Equal(context.Canceled))
+		})
+	})
+
+	Context("when a gcd is computed", func() {
+		It("should normalise it for the ring", func() {
+			a := expandString("6 (x + 1)^2 (x - 3)")
+			b := expandString("4 (x + 1) (x + 2)")
+
+			gcd, err := GCD(a, b)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(gcd.String()).To(Equal("2 * x + 2"))
+
+			a, err = a.To(Rationals)
+			Expect(err).ShouldNot(HaveOccurred())
+			b, err = b.To(Rationals)
+			Expect(err).ShouldNot(HaveOccurred())
+
+			gcd, err = GCD(a, b)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(gcd.String()).To(Equal("x + 1"))
+
+			gcd, err = GCD(expandRing("(x + 1)(x + 2)", mod7), expandRing("(x - 6)(x + 3)", mod7))
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(gcd.String()).To(Equal("x + 1"))
+		})
+	})
+
+	Context("when a gcd of multivariate polynomials is computed", func() {
+		It("should find the common factors in all variables", func() {
+			gcd, err := GCD(expandString("(x + y)^2 (x - 2 y z)"), expandString("3 (x + y) (x z + 1)"))
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(gcd.String()).To(Equal("x + y"))
+
+			gcd, err = GCD(expandString("x + y"), expandString("x"))
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(gcd.String()).To(Equal("1"))
+
+			gcd, err = GCD(expandRing("(x y + 2)(x - y)", mod7), expandRing("(x y - 5)(y + 1)", mod7))
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(gcd.String()).To(Equal("x * y + 2"))
+		})
+
+		It("should respect the limits", func() {
+			a, b := expandString("(x + y + 1)^4 (x - y)"), expandString("(x + y + 1)^3 (x + 2 y)")
+
+			_, err := GCDContext(context.Background(), a, b, Limits{MaxTerms: 10})
+			Expect(err).Should(BeAssignableToTypeOf(&LimitError{}))
+
+			gcd, err := GCDContext(context.Background(), a, b, Limits{})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(gcd.String()).To(Equal(expandString("(x + y + 1)^3").String()))
+		})
+	})
+})
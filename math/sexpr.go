@@ -0,0 +1,308 @@
+// Copyright (c) 2017 Przemysław Dobrowolski
+//
+// This file is part of the math-mod, a package for symbolic manipulation
+// of large algebraic expressions.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package math
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Sexpr returns the expression as a Lisp S-expression such as
+// (+ x (* 2 (sin y))). Chains of sums and products nested on the left are
+// written as one list, (+ a b c) standing for (a + b) + c.
+func (expr *Expr) Sexpr() string {
+	var builder strings.Builder
+	expr.writeSexpr(&builder)
+	return builder.String()
+}
+
+func (expr *Expr) writeSexpr(builder *strings.Builder) {
+	if expr.IsLeaf() {
+		builder.WriteString(expr.Token.String())
+		return
+	}
+
+	// operands of the chain, the innermost first
+	var args []*Expr
+	node := expr
+
+	for {
+		for i := len(node.Args) - 1; i > 0; i-- {
+			args = append(args, node.Args[i])
+		}
+
+		left := node.Args[0]
+		if (expr.Token.Kind != KindPlus && expr.Token.Kind != KindMul) || left.Token.Kind != expr.Token.Kind {
+			args = append(args, left)
+			break
+		}
+		node = left
+	}
+
+	builder.WriteString("(" + expr.Token.String())
+	for i := len(args) - 1; i >= 0; i-- {
+		builder.WriteString(" ")
+		args[i].writeSexpr(builder)
+	}
+	builder.WriteString(")")
+}
+
+type sexprList struct {
+	head Token
+	pos  int
+	// whether the head was read
+	named bool
+	args  []*Expr
+}
+
+// ParseSexpr reads an expression written as an S-expression, as printed by
+// Sexpr. Sums and products take two or more operands, other operators two
+// and functions one. Syntax errors are reported as *ParseError.
+func ParseSexpr(reader io.Reader) (*Expr, error) {
+	data, err := io.ReadAll(reader)
+
+	if err != nil {
+		return nil, err
+	}
+
+	exprs, _, err := parseSexprs(data, 0, true)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return exprs[0], nil
+}
+
+func ParseSexprString(sexpr string) (*Expr, error) {
+	return ParseSexpr(strings.NewReader(sexpr))
+}
+
+// ParseSexprStatements reads statements as printed by format --to sexpr,
+// one per line or separated by ';': expressions, assignments (:= name expr)
+// and equations (= lhs rhs). Like ParseStatements it reports syntax errors
+// in Err of each statement.
+func ParseSexprStatements(reader io.Reader) ([]Statement, error) {
+	data, err := io.ReadAll(reader)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var stmts []Statement
+
+	for _, span := range splitStatements(data, true) {
+		stmt := parseSexprStatement(data, span[0], span[1])
+		stmt.Line = 1 + bytes.Count(data[:span[0]], []byte("\n"))
+		stmts = append(stmts, stmt)
+	}
+
+	return stmts, nil
+}
+
+// parseSexprStatement parses the statement in data[start:end].
+func parseSexprStatement(data []byte, start, end int) Statement {
+	data = data[:end]
+	open := nextSignificant(data, start)
+	form := ""
+
+	if open < end && data[open] == '(' {
+		switch head := nextSignificant(data, open+1); {
+		case bytes.HasPrefix(data[head:], []byte(":=")):
+			form = ":="
+		case head < end && data[head] == '=':
+			form = "="
+		}
+	}
+
+	if form == "" {
+		exprs, _, err := parseSexprs(data[start:], start, true)
+
+		if err != nil {
+			return Statement{Err: err}
+		}
+
+		return Statement{Expr: exprs[0]}
+	}
+
+	// the operands run to the bracket closing the form
+	depth, close := 0, -1
+
+	for i := open; i < end && close < 0; i++ {
+		if j := skipped(data, i); j > i {
+			i = j - 1
+			continue
+		}
+
+		switch data[i] {
+		case '(':
+			depth++
+		case ')':
+			if depth--; depth == 0 {
+				close = i
+			}
+		}
+	}
+
+	if close < 0 {
+		return Statement{Err: &ParseError{Pos: open, Msg: "unclosed bracket"}}
+	}
+
+	if next := nextSignificant(data, close+1); next < end {
+		return Statement{Err: &ParseError{Pos: next, Msg: "unexpected input after expression"}}
+	}
+
+	operands := bytes.Index(data[open:], []byte(form)) + open + len(form)
+	exprs, starts, err := parseSexprs(data[operands:close], operands, false)
+
+	if err != nil {
+		return Statement{Err: err}
+	}
+
+	if len(exprs) != 2 {
+		return Statement{Err: &ParseError{Pos: close, Msg: fmt.Sprintf("%v takes 2 operands, got %d", form, len(exprs))}}
+	}
+
+	if form == "=" {
+		return Statement{Expr: exprs[0], Rhs: exprs[1]}
+	}
+
+	if !exprs[0].IsLeaf() || exprs[0].Token.Kind != KindVar {
+		return Statement{Err: &ParseError{Pos: starts[0], Msg: "assignment requires a variable name"}}
+	}
+
+	return Statement{Name: exprs[0].Token.Value.(string), Expr: exprs[1]}
+}
+
+// parseSexprs reads the S-expressions in the data found at the offset of
+// the input, which positions are counted from, and returns them with the
+// positions they start at. With single exactly one must be found.
+func parseSexprs(data []byte, offset int, single bool) ([]*Expr, []int, error) {
+	tokens, positions, err := lexNotation(data)
+
+	if err != nil {
+		return nil, nil, shiftError(err, offset)
+	}
+
+	var lists []*sexprList
+	var exprs []*Expr
+	var starts []int
+
+	for i, token := range tokens {
+		pos := offset + positions[i]
+
+		if single && len(exprs) > 0 {
+			return nil, nil, &ParseError{Pos: pos, Msg: "unexpected input after expression"}
+		}
+
+		var expr *Expr
+		start := pos
+
+		switch {
+		case token.Kind == KindOpen:
+			lists = append(lists, &sexprList{pos: pos})
+			continue
+
+		case token.Kind == KindClose:
+			if len(lists) == 0 {
+				return nil, nil, &ParseError{Pos: pos, Msg: "unmatched closing bracket"}
+			}
+
+			list := lists[len(lists)-1]
+			lists = lists[:len(lists)-1]
+			start = list.pos
+
+			if expr, err = list.expr(pos); err != nil {
+				return nil, nil, err
+			}
+
+		case len(lists) > 0 && !lists[len(lists)-1].named:
+			if token.Kind != KindFunc {
+				if _, isOper := OperProps[token.Kind]; !isOper {
+					return nil, nil, &ParseError{Pos: pos, Msg: fmt.Sprintf("expected operator or function: %v", token)}
+				}
+			}
+
+			lists[len(lists)-1].head = token
+			lists[len(lists)-1].named = true
+			continue
+
+		case token.Kind == KindInt || token.Kind == KindVar:
+			expr = NewLeaf(token)
+
+		default:
+			return nil, nil, &ParseError{Pos: pos, Msg: fmt.Sprintf("unexpected operator outside of list head: %v", token)}
+		}
+
+		if len(lists) == 0 {
+			exprs = append(exprs, expr)
+			starts = append(starts, start)
+		} else {
+			lists[len(lists)-1].args = append(lists[len(lists)-1].args, expr)
+		}
+	}
+
+	if len(lists) > 0 {
+		return nil, nil, &ParseError{Pos: lists[len(lists)-1].pos, Msg: "unclosed bracket"}
+	}
+
+	if single && len(exprs) == 0 {
+		return nil, nil, &ParseError{Pos: offset + len(data), Msg: "empty expression"}
+	}
+
+	return exprs, starts, nil
+}
+
+// expr builds the node of a list closed at the position.
+func (list *sexprList) expr(pos int) (*Expr, error) {
+	if !list.named {
+		return nil, &ParseError{Pos: list.pos, Msg: "empty list"}
+	}
+
+	switch {
+	case list.head.Kind == KindFunc:
+		if len(list.args) != 1 {
+			return nil, &ParseError{Pos: pos, Msg: fmt.Sprintf("function %v takes 1 argument, got %d", list.head, len(list.args))}
+		}
+		return NewNode(list.head, list.args[0]), nil
+
+	case list.head.Kind == KindPlus || list.head.Kind == KindMul:
+		if len(list.args) < 2 {
+			return nil, &ParseError{Pos: pos, Msg: fmt.Sprintf("operator %v takes at least 2 operands, got %d", list.head, len(list.args))}
+		}
+
+	default:
+		if len(list.args) != 2 {
+			return nil, &ParseError{Pos: pos, Msg: fmt.Sprintf("operator %v takes 2 operands, got %d", list.head, len(list.args))}
+		}
+	}
+
+	expr := list.args[0]
+	for _, arg := range list.args[1:] {
+		expr = NewNode(list.head, expr, arg)
+	}
+	return expr, nil
+}
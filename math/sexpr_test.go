@@ -0,0 +1,103 @@
+// Copyright (c) 2017 Przemysław Dobrowolski
+//
+// This file is part of the math-mod, a package for symbolic manipulation
+// of large algebraic expressions.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package math
+
+import (
+	"strings"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Sexpr Object", func() {
+	Context("when a tree is printed as an S-expression", func() {
+		It("should join chains of sums and products", func() {
+			Expect(parseTree("a + b + c * d * e - sin(x)").Sexpr()).To(Equal("(- (+ a b (* c d e)) (sin x))"))
+			Expect(parseTree("a + (b + c)").Sexpr()).To(Equal("(+ a (+ b c))"))
+			Expect(parseTree("x").Sexpr()).To(Equal("x"))
+		})
+	})
+
+	Context("when an S-expression is parsed", func() {
+		It("should read printed S-expressions back", func() {
+			for _, src := range []string{"a + b + c * d * e - sin(x)", "a + (b + c) ^ 2 / y[1,2]", "x"} {
+				expr := parseTree(src)
+
+				parsed, err := ParseSexprString(expr.Sexpr())
+				Expect(err).ShouldNot(HaveOccurred())
+				Expect(parsed.Equal(expr)).To(BeTrue())
+			}
+
+			parsed, err := ParseSexprString("(* -3 (- 3 x))")
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(parsed).To(Equal(NewNode(NewMul(), NewLeaf(NewInt(-3)), NewNode(NewMinus(), NewLeaf(NewInt(3)), NewLeaf(NewVar("x"))))))
+		})
+
+		It("should check the lists", func() {
+			_, err := ParseSexprString("(+ x)")
+			Expect(err).To(Equal(&ParseError{Pos: 4, Msg: "operator + takes at least 2 operands, got 1"}))
+
+			_, err = ParseSexprString("(- x y z)")
+			Expect(err).To(Equal(&ParseError{Pos: 8, Msg: "operator - takes 2 operands, got 3"}))
+
+			_, err = ParseSexprString("(sin x y)")
+			Expect(err).To(Equal(&ParseError{Pos: 8, Msg: "function sin takes 1 argument, got 2"}))
+
+			_, err = ParseSexprString("(x y)")
+			Expect(err).To(Equal(&ParseError{Pos: 1, Msg: "expected operator or function: x"}))
+
+			_, err = ParseSexprString("(+ x +)")
+			Expect(err).To(Equal(&ParseError{Pos: 5, Msg: "unexpected operator outside of list head: +"}))
+
+			_, err = ParseSexprString("(+ x (* y z)")
+			Expect(err).To(Equal(&ParseError{Pos: 0, Msg: "unclosed bracket"}))
+
+			_, err = ParseSexprString("x y")
+			Expect(err).To(Equal(&ParseError{Pos: 2, Msg: "unexpected input after expression"}))
+
+			_, err = ParseSexprString("()")
+			Expect(err).To(Equal(&ParseError{Pos: 0, Msg: "empty list"}))
+
+			_, err = ParseSexprString(")")
+			Expect(err).To(Equal(&ParseError{Pos: 0, Msg: "unmatched closing bracket"}))
+		})
+
+		It("should read statements as printed", func() {
+			stmts, err := ParseSexprStatements(strings.NewReader("(:= a (+ x 1))\n(= (^ (- a 3) 2)\n   y)\n(:= 2 x); (= x)\nx # note\n(:= a b) c\n"))
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(stmts).To(HaveLen(6))
+
+			Expect(stmts[0].Name).To(Equal("a"))
+			Expect(stmts[0].Expr.String()).To(Equal("x + 1"))
+			Expect(stmts[1].Expr.String()).To(Equal("( a - 3 ) ^ 2"))
+			Expect(stmts[1].Rhs.String()).To(Equal("y"))
+			Expect(stmts[1].Line).To(Equal(2))
+			Expect(stmts[2].Err).To(Equal(&ParseError{Pos: 42, Msg: "assignment requires a variable name"}))
+			Expect(stmts[3].Err).To(Equal(&ParseError{Pos: 52, Msg: "= takes 2 operands, got 1"}))
+			Expect(stmts[4].Expr.String()).To(Equal("x"))
+			Expect(stmts[4].Line).To(Equal(5))
+			Expect(stmts[5].Err).To(Equal(&ParseError{Pos: 72, Msg: "unexpected input after expression"}))
+		})
+	})
+})
@@ -0,0 +1,379 @@
+// Copyright (c) 2017 Przemysław Dobrowolski
+//
+// This file is part of the math-mod, a package for symbolic manipulation
+// of large algebraic expressions.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package math
+
+import (
+	"bytes"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Statement is one statement of an input: an expression, an assignment
+// "name := expr", an equation "lhs = rhs" or an include directive. Name is set for assignments and
+// Rhs for equations, whose left-hand side is kept in Expr.
+type Statement struct {
+	Name string
+	Expr *Expr
+	Rhs  *Expr
+	// Include is the path of the directive include "path".
+	Include string
+	// Line is the line the statement starts on, counted from 1.
+	Line int
+	// Err is the syntax error of the statement, if any.
+	Err error
+}
+
+func (stmt Statement) String() string {
+	switch {
+	case stmt.Include != "":
+		return "include " + strconv.Quote(stmt.Include)
+	case stmt.Name != "":
+		return stmt.Name + " := " + stmt.Expr.String()
+	case stmt.Rhs != nil:
+		return stmt.Expr.String() + " = " + stmt.Rhs.String()
+	default:
+		return stmt.Expr.String()
+	}
+}
+
+// Latex renders the statement for LaTeX math mode.
+func (stmt Statement) Latex() string {
+	switch {
+	case stmt.Include != "":
+		return `\text{include ` + strconv.Quote(stmt.Include) + "}"
+	case stmt.Name != "":
+		return latexName(stmt.Name) + " := " + stmt.Expr.Latex()
+	case stmt.Rhs != nil:
+		return stmt.Expr.Latex() + " = " + stmt.Rhs.Latex()
+	default:
+		return stmt.Expr.Latex()
+	}
+}
+
+// Sexpr returns the statement as an S-expression, assignments and equations
+// written as (:= name expr) and (= lhs rhs).
+func (stmt Statement) Sexpr() string {
+	switch {
+	case stmt.Include != "":
+		return "(include " + strconv.Quote(stmt.Include) + ")"
+	case stmt.Name != "":
+		return "(:= " + stmt.Name + " " + stmt.Expr.Sexpr() + ")"
+	case stmt.Rhs != nil:
+		return "(= " + stmt.Expr.Sexpr() + " " + stmt.Rhs.Sexpr() + ")"
+	default:
+		return stmt.Expr.Sexpr()
+	}
+}
+
+// ParseStatements reads statements separated by ';' or newlines. A newline
+// inside brackets, after an operator or before a line starting with an
+// operator continues the statement, so long expressions may span lines.
+// Syntax errors do not stop parsing; they are reported in Err of each
+// statement, as *ParseError with offsets counted from the input start.
+func ParseStatements(reader io.Reader) ([]Statement, error) {
+	return new(Parser).ParseStatements(reader)
+}
+
+func ParseStatementsString(src string) ([]Statement, error) {
+	return ParseStatements(strings.NewReader(src))
+}
+
+func (parser *Parser) ParseStatements(reader io.Reader) ([]Statement, error) {
+	data, err := io.ReadAll(reader)
+
+	if err != nil {
+		return nil, err
+	}
+
+	var stmts []Statement
+
+	for _, span := range splitStatements(data, false) {
+		stmt, ok := parseInclude(data, span[0], span[1])
+
+		if !ok {
+			stmt = parseStatement(data, span[0], span[1], parser.parseSpan)
+		}
+
+		stmt.Line = 1 + bytes.Count(data[:span[0]], []byte("\n"))
+		stmts = append(stmts, stmt)
+	}
+
+	return stmts, nil
+}
+
+// parseLines reads statements written one per line or separated by ';',
+// their expressions parsed by parseSpan. Only brackets continue a statement
+// over a line break, as notations such as postfix may end a line with an
+// operator.
+func parseLines(data []byte, parseSpan func(data []byte, start, end int) (*Expr, error)) []Statement {
+	var stmts []Statement
+
+	for _, span := range splitStatements(data, true) {
+		stmt := parseStatement(data, span[0], span[1], parseSpan)
+		stmt.Line = 1 + bytes.Count(data[:span[0]], []byte("\n"))
+		stmts = append(stmts, stmt)
+	}
+
+	return stmts
+}
+
+// continuesStatement reports whether a statement continues over a line
+// break before or after the character.
+func continuesStatement(c byte) bool {
+	return strings.IndexByte("+-*/^(=:", c) >= 0
+}
+
+// skipped returns the index past the comment or quoted string starting at i,
+// or i if there is none. Comments end before their newline.
+func skipped(data []byte, i int) int {
+	switch {
+	case data[i] == '#', data[i] == '/' && i+1 < len(data) && data[i+1] == '/':
+		return skipComment(data, i)
+	case data[i] == '"':
+		for j := i + 1; j < len(data); j++ {
+			switch data[j] {
+			case '\\':
+				j++
+			case '"':
+				return j + 1
+			}
+		}
+		return len(data)
+	}
+	return i
+}
+
+func isSpace(c byte) bool {
+	return strings.IndexByte(" \t\n\v\f\r", c) >= 0
+}
+
+// nextSignificant returns the index of the first character from i on
+// which is neither whitespace nor in a comment, or the length of data.
+func nextSignificant(data []byte, i int) int {
+	for i < len(data) {
+		if j := skipped(data, i); j > i && data[i] != '"' {
+			i = j
+		} else if isSpace(data[i]) {
+			i++
+		} else {
+			break
+		}
+	}
+	return i
+}
+
+// splitStatements returns the spans of statements which are not blank. With
+// lines, every line break outside brackets ends a statement.
+func splitStatements(data []byte, lines bool) (spans [][2]int) {
+	depth, start := 0, 0
+
+	// last character of the statement outside comments, 0 if none
+	var last byte
+
+	add := func(end int) {
+		if last != 0 {
+			spans = append(spans, [2]int{start, end})
+		}
+		start, last = end+1, 0
+	}
+
+	for i := 0; i < len(data); i++ {
+		if j := skipped(data, i); j > i {
+			if data[i] == '"' {
+				last = '"'
+			}
+			i = j - 1
+			continue
+		}
+
+		switch data[i] {
+		case '(', '[':
+			depth++
+		case ')', ']':
+			depth--
+		case ';':
+			if depth <= 0 {
+				add(i)
+				continue
+			}
+		case '\n':
+			if last == 0 {
+				// the statement starts on a later line
+				start = i + 1
+				continue
+			}
+
+			if depth > 0 || !lines && continuesStatement(last) {
+				continue
+			}
+
+			if next := nextSignificant(data, i+1); !lines && next < len(data) {
+				if data[next] != '(' && (continuesStatement(data[next]) || data[next] == ')') {
+					continue
+				}
+			}
+
+			add(i)
+			continue
+		}
+
+		if !isSpace(data[i]) {
+			last = data[i]
+		}
+	}
+
+	add(len(data))
+	return
+}
+
+// parseStatement parses the statement in data[start:end], its expressions
+// parsed by parseSpan.
+func parseStatement(data []byte, start, end int, parseSpan func(data []byte, start, end int) (*Expr, error)) Statement {
+	depth, assign, equals := 0, -1, -1
+
+	for i := start; i < end; i++ {
+		if j := skipped(data[:end], i); j > i {
+			i = j - 1
+			continue
+		}
+
+		switch data[i] {
+		case '(', '[':
+			depth++
+		case ')', ']':
+			depth--
+		case ':':
+			if depth == 0 && i+1 < end && data[i+1] == '=' && assign < 0 && equals < 0 {
+				assign = i
+				i++
+			}
+		case '=':
+			if depth != 0 {
+				continue
+			}
+			if assign >= 0 || equals >= 0 {
+				return Statement{Err: &ParseError{Pos: i, Msg: "unexpected equals sign"}}
+			}
+			equals = i
+		}
+	}
+
+	var stmt Statement
+
+	switch {
+	case assign >= 0:
+		name, err := parseName(data, start, assign)
+
+		if err != nil {
+			return Statement{Err: err}
+		}
+
+		stmt.Name = name
+		stmt.Expr, stmt.Err = parseSpan(data, assign+2, end)
+
+	case equals >= 0:
+		if stmt.Expr, stmt.Err = parseSpan(data, start, equals); stmt.Err == nil {
+			stmt.Rhs, stmt.Err = parseSpan(data, equals+1, end)
+		}
+
+	default:
+		stmt.Expr, stmt.Err = parseSpan(data, start, end)
+	}
+
+	if stmt.Err != nil {
+		return Statement{Err: stmt.Err}
+	}
+
+	return stmt
+}
+
+// parseInclude parses the directive include "path" in data[start:end] and
+// reports whether the statement is one.
+func parseInclude(data []byte, start, end int) (Statement, bool) {
+	const keyword = "include"
+
+	i := start
+	for i < end && isSpace(data[i]) {
+		i++
+	}
+
+	if !bytes.HasPrefix(data[i:end], []byte(keyword)) {
+		return Statement{}, false
+	}
+
+	i += len(keyword)
+	for i < end && isSpace(data[i]) {
+		i++
+	}
+
+	if i == end || data[i] != '"' {
+		return Statement{}, false
+	}
+
+	j := skipped(data[:end], i)
+	path, err := strconv.Unquote(string(data[i:j]))
+
+	if err != nil || path == "" {
+		return Statement{Err: &ParseError{Pos: i, Msg: "invalid include path"}}, true
+	}
+
+	if next := nextSignificant(data[:end], j); next < end {
+		return Statement{Err: &ParseError{Pos: next, Msg: "unexpected input after include path"}}, true
+	}
+
+	return Statement{Include: path}, true
+}
+
+// parseName parses the target of an assignment in data[start:end].
+func parseName(data []byte, start, end int) (string, error) {
+	tokens, positions, err := ParseInfixPositions(bytes.NewReader(data[start:end]))
+
+	if err != nil {
+		return "", shiftError(err, start)
+	}
+
+	if len(tokens) != 1 || tokens[0].Kind != KindVar {
+		pos := end
+		if len(positions) > 0 {
+			pos = start + positions[0]
+		}
+		return "", &ParseError{Pos: pos, Msg: "assignment requires a variable name"}
+	}
+
+	return tokens[0].Value.(string), nil
+}
+
+// parseSpan parses the expression in data[start:end].
+func (parser *Parser) parseSpan(data []byte, start, end int) (*Expr, error) {
+	return parser.parse(data[start:end], start)
+}
+
+// shiftError moves the position of a syntax error by offset bytes.
+func shiftError(err error, offset int) error {
+	if parseErr, ok := err.(*ParseError); ok {
+		return &ParseError{Pos: parseErr.Pos + offset, Msg: parseErr.Msg}
+	}
+	return err
+}
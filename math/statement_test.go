@@ -0,0 +1,109 @@
+// Copyright (c) 2017 Przemysław Dobrowolski
+//
+// This file is part of the math-mod, a package for symbolic manipulation
+// of large algebraic expressions.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package math
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func statementStrings(src string) (result []string) {
+	stmts, err := ParseStatementsString(src)
+	Expect(err).ShouldNot(HaveOccurred())
+
+	for _, stmt := range stmts {
+		Expect(stmt.Err).ShouldNot(HaveOccurred())
+		result = append(result, stmt.String())
+	}
+	return
+}
+
+var _ = Describe("Statement Object", func() {
+	Context("when statements are separated", func() {
+		It("should split at semicolons and newlines", func() {
+			Expect(statementStrings("x + 1; y\n\n2 z\n")).To(Equal([]string{"x + 1", "y", "2 * z"}))
+		})
+
+		It("should continue statements over line breaks", func() {
+			Expect(statementStrings("x +\ny\n(a\n+ b)\n* c\nd")).To(Equal([]string{"x + y", "( a + b ) * c", "d"}))
+			Expect(statementStrings("(x + y)\n(x - y)")).To(Equal([]string{"x + y", "x - y"}))
+		})
+	})
+
+	Context("when assignments and equations are parsed", func() {
+		It("should keep their parts", func() {
+			stmts, err := ParseStatementsString("f := x^2 + 1\nx^2 = 2 y\n")
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(stmts).To(HaveLen(2))
+
+			Expect(stmts[0].Name).To(Equal("f"))
+			Expect(stmts[0].Expr.String()).To(Equal("x ^ 2 + 1"))
+			Expect(stmts[0].Rhs).To(BeNil())
+			Expect(stmts[0].String()).To(Equal("f := x ^ 2 + 1"))
+
+			Expect(stmts[1].Line).To(Equal(2))
+			Expect(stmts[1].Name).To(Equal(""))
+			Expect(stmts[1].String()).To(Equal("x ^ 2 = 2 * y"))
+		})
+	})
+
+	Context("when statements have errors", func() {
+		It("should report them per statement", func() {
+			stmts, err := ParseStatementsString("x +* y; z\n2 x := 1\na = b = c\n(q")
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(stmts).To(HaveLen(5))
+
+			Expect(stmts[0].Err).To(Equal(&ParseError{Pos: 3, Msg: "missing operand for operator: *"}))
+			Expect(stmts[1].Err).ShouldNot(HaveOccurred())
+			Expect(stmts[2].Err).To(Equal(&ParseError{Pos: 10, Msg: "assignment requires a variable name"}))
+			Expect(stmts[3].Err).To(Equal(&ParseError{Pos: 25, Msg: "unexpected equals sign"}))
+			Expect(stmts[3].Line).To(Equal(3))
+			Expect(stmts[4].Err).To(Equal(&ParseError{Pos: 29, Msg: "unclosed bracket"}))
+		})
+	})
+
+	Context("when statements have comments and includes", func() {
+		It("should skip the comments", func() {
+			Expect(statementStrings("x + # ; (\n y // z\n# only a comment\nw")).To(Equal([]string{"x + y", "w"}))
+
+			stmts, err := ParseStatementsString("// header\n\n# defs\ninclude \"a\"")
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(stmts).To(HaveLen(1))
+			Expect(stmts[0].Include).To(Equal("a"))
+			Expect(stmts[0].Line).To(Equal(4))
+		})
+
+		It("should parse include directives", func() {
+			stmts, err := ParseStatementsString("include \"defs;#.mm\" # comment\ninclude \"a\" b\ninclude x")
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(stmts).To(HaveLen(3))
+
+			Expect(stmts[0].Include).To(Equal("defs;#.mm"))
+			Expect(stmts[0].String()).To(Equal(`include "defs;#.mm"`))
+			Expect(stmts[1].Err).To(Equal(&ParseError{Pos: 42, Msg: "unexpected input after include path"}))
+			Expect(stmts[2].Err).ShouldNot(HaveOccurred())
+			Expect(stmts[2].String()).To(Equal("include * x"))
+		})
+	})
+})
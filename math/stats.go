@@ -0,0 +1,106 @@
+// Copyright (c) 2017 Przemysław Dobrowolski
+//
+// This file is part of the math-mod, a package for symbolic manipulation
+// of large algebraic expressions.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package math
+
+import (
+	"sort"
+)
+
+// Stats summarises the size and shape of an expression tree. Distinct counts
+// its distinct subtrees, the number of nodes it takes as a DAG; it is only
+// computed by Table.Stats.
+type Stats struct {
+	Nodes     int            `json:"nodes"`
+	Distinct  int            `json:"distinct,omitempty"`
+	Depth     int            `json:"depth"`
+	Kinds     map[string]int `json:"kinds"`
+	Variables []string       `json:"variables"`
+}
+
+func (expr *Expr) Stats() Stats {
+	stats := Stats{Kinds: make(map[string]int)}
+	expr.collectStats(&stats, 1)
+
+	for name := range expr.Vars() {
+		stats.Variables = append(stats.Variables, name)
+	}
+	sort.Strings(stats.Variables)
+	return stats
+}
+
+func (expr *Expr) collectStats(stats *Stats, depth int) {
+	stats.Nodes++
+	stats.Kinds[expr.Token.Kind.String()]++
+
+	if depth > stats.Depth {
+		stats.Depth = depth
+	}
+
+	for _, arg := range expr.Args {
+		arg.collectStats(stats, depth+1)
+	}
+}
+
+// Stats summarises the interned expression as Expr.Stats does for its tree
+// and counts its distinct subtrees. Every distinct node is visited once;
+// counts of tree nodes saturate at the largest int.
+func (table *Table) Stats(id NodeID) Stats {
+	stats := Stats{Kinds: make(map[string]int)}
+
+	// operands are interned before their nodes, so going down from id
+	// visits every node after all nodes it occurs in
+	occurs := make([]int, id+1)
+	depths := make([]int, id+1)
+	occurs[id], depths[id] = 1, 1
+
+	for n := id; n >= 0; n-- {
+		if occurs[n] == 0 {
+			continue
+		}
+
+		node := table.nodes[n]
+		stats.Distinct++
+		stats.Nodes = addSaturating(stats.Nodes, occurs[n])
+		kind := node.token.Kind.String()
+		stats.Kinds[kind] = addSaturating(stats.Kinds[kind], occurs[n])
+
+		if depths[n] > stats.Depth {
+			stats.Depth = depths[n]
+		}
+
+		if node.token.Kind == KindVar {
+			stats.Variables = append(stats.Variables, node.token.Value.(string))
+		}
+
+		for _, arg := range node.args {
+			occurs[arg] = addSaturating(occurs[arg], occurs[n])
+			if depths[n]+1 > depths[arg] {
+				depths[arg] = depths[n] + 1
+			}
+		}
+	}
+
+	sort.Strings(stats.Variables)
+	return stats
+}
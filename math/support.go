@@ -0,0 +1,501 @@
+// Copyright (c) 2017 Przemysław Dobrowolski
+//
+// This file is part of the math-mod, a package for symbolic manipulation
+// of large algebraic expressions.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package math
+
+import (
+	"context"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Support returns the variables of the polynomial in alphabetical order and
+// the exponent vectors of its terms, sorted lexicographically. The variables
+// of a constant are an empty, not nil, slice.
+func (poly Poly) Support() (vars []string, points [][]int) {
+	degrees := poly.DegreesIn()
+	vars = make([]string, 0, len(degrees))
+
+	for name := range degrees {
+		vars = append(vars, name)
+	}
+	sort.Strings(vars)
+
+	for _, term := range poly.terms {
+		point := make([]int, len(vars))
+		for i, name := range vars {
+			point[i] = term.Mono.Exp(name)
+		}
+		points = append(points, point)
+	}
+
+	sort.Slice(points, func(i, j int) bool {
+		return comparePoints(points[i], points[j]) < 0
+	})
+	return
+}
+
+func comparePoints(a, b []int) int {
+	for i := range a {
+		switch {
+		case a[i] < b[i]:
+			return -1
+		case a[i] > b[i]:
+			return 1
+		}
+	}
+	return 0
+}
+
+// Polytope is the Newton polytope of a polynomial, the convex hull of its
+// support. Facets are given for up to 3 variables as indices of vertices;
+// they are the faces of dimension one less than the polytope, which may be
+// lower dimensional than the space: endpoints of a segment, edges of a
+// polygon or polygons bounding a solid, the latter two in counterclockwise
+// order seen from outside.
+type Polytope struct {
+	Vars      []string `json:"vars"`
+	Dimension int      `json:"dimension"`
+	Vertices  [][]int  `json:"vertices"`
+	Facets    [][]int  `json:"facets,omitempty"`
+}
+
+// NewtonPolytope computes the Newton polytope of a non-zero polynomial.
+// Vertices are found with an exact linear program per support point, so
+// the cost grows quadratically with the number of terms.
+func NewtonPolytope(poly Poly) Polytope {
+	polytope, _ := newtonPolytope(poly, nil)
+	return polytope
+}
+
+// NewtonPolytopeContext works like NewtonPolytope, but gives up when the
+// context is done.
+func NewtonPolytopeContext(ctx context.Context, poly Poly, limits Limits) (Polytope, error) {
+	return newtonPolytope(poly, newGuard(ctx, limits))
+}
+
+func newtonPolytope(poly Poly, g *guard) (Polytope, error) {
+	vars, points := poly.Support()
+	polytope := Polytope{
+		Vars:      vars,
+		Dimension: affineDimension(points),
+	}
+
+	if polytope.Dimension == 2 && len(vars) <= 3 {
+		axes := planeAxes(points)
+		polytope.Vertices = convexPolygon(points, axes[0], axes[1])
+		polytope.Facets = polygonEdges(len(polytope.Vertices))
+		return polytope, nil
+	}
+
+	vertices := points
+
+	for i := 0; i < len(vertices); {
+		others := make([][]int, 0, len(vertices)-1)
+		others = append(others, vertices[:i]...)
+		others = append(others, vertices[i+1:]...)
+
+		inside, err := inConvexHull(vertices[i], others, g)
+
+		if err != nil {
+			return Polytope{}, err
+		}
+
+		if inside {
+			vertices = others
+		} else {
+			i++
+		}
+	}
+
+	polytope.Vertices = vertices
+
+	switch {
+	case polytope.Dimension == 1:
+		polytope.Facets = [][]int{{0}, {1}}
+	case polytope.Dimension == 3 && len(vars) == 3:
+		polytope.Facets = solidFacets(vertices)
+	}
+	return polytope, nil
+}
+
+// affineDimension returns the dimension of the affine hull of the points.
+func affineDimension(points [][]int) int {
+	if len(points) == 0 {
+		return -1
+	}
+
+	var rows [][]*big.Rat
+
+	for _, point := range points[1:] {
+		row := make([]*big.Rat, len(point))
+		for i := range point {
+			row[i] = big.NewRat(int64(point[i]-points[0][i]), 1)
+		}
+		rows = append(rows, row)
+	}
+
+	return rank(rows)
+}
+
+// rank returns the rank of a matrix, which it overwrites.
+func rank(rows [][]*big.Rat) int {
+	r := 0
+
+	for col := 0; len(rows) > 0 && col < len(rows[0]) && r < len(rows); col++ {
+		pivot := r
+		for pivot < len(rows) && rows[pivot][col].Sign() == 0 {
+			pivot++
+		}
+		if pivot == len(rows) {
+			continue
+		}
+		rows[r], rows[pivot] = rows[pivot], rows[r]
+
+		for i := r + 1; i < len(rows); i++ {
+			factor := new(big.Rat).Quo(rows[i][col], rows[r][col])
+			for j := col; j < len(rows[i]); j++ {
+				rows[i][j].Sub(rows[i][j], new(big.Rat).Mul(factor, rows[r][j]))
+			}
+		}
+		r++
+	}
+	return r
+}
+
+// inConvexHull reports whether the point is a convex combination of the
+// others: whether lambda >= 0 with sum 1 and sum lambda_i*others_i = point
+// exists. It runs the first phase of the simplex method with Bland's rule,
+// checking the guard at every pivot.
+func inConvexHull(point []int, others [][]int, g *guard) (bool, error) {
+	if len(others) == 0 {
+		return false, nil
+	}
+
+	rows, cols := len(point)+1, len(others)
+	tableau := make([][]*big.Rat, rows)
+
+	// columns: lambdas, artificial variables, right-hand side
+	for i := range tableau {
+		tableau[i] = make([]*big.Rat, cols+rows+1)
+		for j := range tableau[i] {
+			tableau[i][j] = new(big.Rat)
+		}
+
+		rhs := int64(1)
+		if i < len(point) {
+			rhs = int64(point[i])
+		}
+		sign := int64(1)
+		if rhs < 0 {
+			sign = -1
+		}
+
+		for j, other := range others {
+			value := int64(1)
+			if i < len(point) {
+				value = int64(other[i])
+			}
+			tableau[i][j].SetInt64(sign * value)
+		}
+		tableau[i][cols+i].SetInt64(1)
+		tableau[i][cols+rows].SetInt64(sign * rhs)
+	}
+
+	basis := make([]int, rows)
+	for i := range basis {
+		basis[i] = cols + i
+	}
+
+	for {
+		if err := g.step(); err != nil {
+			return false, err
+		}
+
+		// reduced costs of minimising the sum of artificial variables
+		entering := -1
+
+		for j := 0; j < cols+rows && entering < 0; j++ {
+			cost := new(big.Rat)
+			if j >= cols {
+				cost.SetInt64(1)
+			}
+			for i, b := range basis {
+				if b >= cols {
+					cost.Sub(cost, tableau[i][j])
+				}
+			}
+			if cost.Sign() < 0 {
+				entering = j
+			}
+		}
+
+		if entering < 0 {
+			break
+		}
+
+		leaving := -1
+		var best *big.Rat
+
+		for i := range tableau {
+			if tableau[i][entering].Sign() <= 0 {
+				continue
+			}
+			ratio := new(big.Rat).Quo(tableau[i][cols+rows], tableau[i][entering])
+			if leaving < 0 || ratio.Cmp(best) < 0 || ratio.Cmp(best) == 0 && basis[i] < basis[leaving] {
+				leaving, best = i, ratio
+			}
+		}
+
+		pivotRow := tableau[leaving]
+		pivot := new(big.Rat).Set(pivotRow[entering])
+		for j := range pivotRow {
+			pivotRow[j].Quo(pivotRow[j], pivot)
+		}
+
+		for i := range tableau {
+			if i == leaving || tableau[i][entering].Sign() == 0 {
+				continue
+			}
+			factor := new(big.Rat).Set(tableau[i][entering])
+			for j := range tableau[i] {
+				tableau[i][j].Sub(tableau[i][j], new(big.Rat).Mul(factor, pivotRow[j]))
+			}
+		}
+		basis[leaving] = entering
+	}
+
+	for i, b := range basis {
+		if b >= cols && tableau[i][cols+rows].Sign() != 0 {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// planeAxes returns two coordinates onto which points spanning a plane
+// project injectively, in the cyclic order of the dropped coordinate so
+// that counterclockwise stays counterclockwise seen from its positive side.
+func planeAxes(points [][]int) [2]int {
+	if len(points[0]) == 2 {
+		return [2]int{0, 1}
+	}
+
+	normal := planeNormal(points)
+	dropped := 0
+
+	for i := range normal {
+		if new(big.Int).Abs(normal[i]).Cmp(new(big.Int).Abs(normal[dropped])) > 0 {
+			dropped = i
+		}
+	}
+
+	axes := [2]int{(dropped + 1) % 3, (dropped + 2) % 3}
+	if normal[dropped].Sign() < 0 {
+		axes[0], axes[1] = axes[1], axes[0]
+	}
+	return axes
+}
+
+// planeNormal returns a normal of the plane spanned by points in space.
+func planeNormal(points [][]int) [3]*big.Int {
+	for i := 1; i < len(points); i++ {
+		for j := i + 1; j < len(points); j++ {
+			normal := cross(points[0], points[i], points[j])
+			if normal[0].Sign() != 0 || normal[1].Sign() != 0 || normal[2].Sign() != 0 {
+				return normal
+			}
+		}
+	}
+	panic("points do not span a plane")
+}
+
+// cross returns (b-a)x(c-a).
+func cross(a, b, c []int) [3]*big.Int {
+	var u, v [3]*big.Int
+
+	for i := range u {
+		u[i] = big.NewInt(int64(b[i] - a[i]))
+		v[i] = big.NewInt(int64(c[i] - a[i]))
+	}
+
+	component := func(i, j int) *big.Int {
+		return new(big.Int).Sub(new(big.Int).Mul(u[i], v[j]), new(big.Int).Mul(u[j], v[i]))
+	}
+	return [3]*big.Int{component(1, 2), component(2, 0), component(0, 1)}
+}
+
+// turn returns the sign of the turn from o to a to b projected onto the
+// axes, positive if counterclockwise.
+func turn(o, a, b []int, x, y int) int {
+	left := new(big.Int).Mul(big.NewInt(int64(a[x]-o[x])), big.NewInt(int64(b[y]-o[y])))
+	right := new(big.Int).Mul(big.NewInt(int64(a[y]-o[y])), big.NewInt(int64(b[x]-o[x])))
+	return left.Cmp(right)
+}
+
+// convexPolygon returns the vertices of the convex hull of coplanar points
+// counterclockwise in the projection onto the axes, computed with Andrew's
+// monotone chain.
+func convexPolygon(points [][]int, x, y int) [][]int {
+	sorted := append([][]int(nil), points...)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i][x] != sorted[j][x] {
+			return sorted[i][x] < sorted[j][x]
+		}
+		return sorted[i][y] < sorted[j][y]
+	})
+
+	var hull [][]int
+
+	for pass := 0; pass < 2; pass++ {
+		start := len(hull)
+		for _, point := range sorted {
+			for len(hull) >= start+2 && turn(hull[len(hull)-2], hull[len(hull)-1], point, x, y) <= 0 {
+				hull = hull[:len(hull)-1]
+			}
+			hull = append(hull, point)
+		}
+		hull = hull[:len(hull)-1]
+
+		for i, j := 0, len(sorted)-1; i < j; i, j = i+1, j-1 {
+			sorted[i], sorted[j] = sorted[j], sorted[i]
+		}
+	}
+	return hull
+}
+
+func polygonEdges(vertices int) (edges [][]int) {
+	for i := 0; i < vertices; i++ {
+		edges = append(edges, []int{i, (i + 1) % vertices})
+	}
+	return
+}
+
+// solidFacets finds the facets of a polytope with vertices spanning space
+// among the planes through triples of vertices.
+func solidFacets(vertices [][]int) (facets [][]int) {
+	seen := make(map[string]bool)
+
+	for i := range vertices {
+		for j := i + 1; j < len(vertices); j++ {
+			for k := j + 1; k < len(vertices); k++ {
+				normal := cross(vertices[i], vertices[j], vertices[k])
+				if normal[0].Sign() == 0 && normal[1].Sign() == 0 && normal[2].Sign() == 0 {
+					continue
+				}
+
+				var on []int
+				above, below := false, false
+
+				for l, vertex := range vertices {
+					side := new(big.Int)
+					for c := range normal {
+						side.Add(side, new(big.Int).Mul(normal[c], big.NewInt(int64(vertex[c]-vertices[i][c]))))
+					}
+					switch side.Sign() {
+					case 0:
+						on = append(on, l)
+					case 1:
+						above = true
+					default:
+						below = true
+					}
+				}
+
+				if above && below {
+					continue
+				}
+
+				key := pointKey(on)
+				if seen[key] {
+					continue
+				}
+				seen[key] = true
+
+				// orient the normal outwards and order the facet around it
+				if above {
+					normal[0].Neg(normal[0])
+					normal[1].Neg(normal[1])
+					normal[2].Neg(normal[2])
+				}
+				facets = append(facets, facetCycle(vertices, on, normal))
+			}
+		}
+	}
+
+	sort.Slice(facets, func(i, j int) bool {
+		a, b := facets[i], facets[j]
+		for k := 0; k < len(a) && k < len(b); k++ {
+			if a[k] != b[k] {
+				return a[k] < b[k]
+			}
+		}
+		return len(a) < len(b)
+	})
+	return
+}
+
+// facetCycle orders the indices of coplanar vertices counterclockwise
+// around the normal, starting from the lowest index.
+func facetCycle(vertices [][]int, on []int, normal [3]*big.Int) []int {
+	dropped := 0
+	for i := range normal {
+		if new(big.Int).Abs(normal[i]).Cmp(new(big.Int).Abs(normal[dropped])) > 0 {
+			dropped = i
+		}
+	}
+
+	x, y := (dropped+1)%3, (dropped+2)%3
+	if normal[dropped].Sign() < 0 {
+		x, y = y, x
+	}
+
+	points := make([][]int, len(on))
+	index := make(map[string]int)
+
+	for i, l := range on {
+		points[i] = vertices[l]
+		index[pointKey(vertices[l])] = l
+	}
+
+	var cycle []int
+	start := 0
+
+	for i, point := range convexPolygon(points, x, y) {
+		cycle = append(cycle, index[pointKey(point)])
+		if cycle[i] < cycle[start] {
+			start = i
+		}
+	}
+	return append(cycle[start:], cycle[:start]...)
+}
+
+func pointKey(values []int) string {
+	parts := make([]string, len(values))
+	for i, value := range values {
+		parts[i] = strconv.Itoa(value)
+	}
+	return strings.Join(parts, ",")
+}
@@ -0,0 +1,115 @@
+// Copyright (c) 2017 Przemysław Dobrowolski
+//
+// This file is part of the math-mod, a package for symbolic manipulation
+// of large algebraic expressions.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package math
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"context"
+)
+
+var _ = Describe("Support Object", func() {
+	Context("when the support is extracted", func() {
+		It("should list exponent vectors", func() {
+			vars, points := expandString("x^2 y + 3 x + y^3 + 1").Support()
+
+			Expect(vars).To(Equal([]string{"x", "y"}))
+			Expect(points).To(Equal([][]int{{0, 0}, {0, 3}, {1, 0}, {2, 1}}))
+		})
+
+		It("should give no variables for a constant", func() {
+			vars, points := expandString("5").Support()
+
+			Expect(vars).To(Equal([]string{}))
+			Expect(points).To(Equal([][]int{{}}))
+			Expect(NewtonPolytope(expandString("5")).Vars).To(Equal([]string{}))
+		})
+	})
+
+	Context("when the Newton polytope is computed", func() {
+		It("should find a polygon", func() {
+			polytope := NewtonPolytope(expandString("1 + x^2 + y^2 + x y"))
+
+			Expect(polytope.Dimension).To(Equal(2))
+			Expect(polytope.Vertices).To(Equal([][]int{{0, 0}, {2, 0}, {0, 2}}))
+			Expect(polytope.Facets).To(Equal([][]int{{0, 1}, {1, 2}, {2, 0}}))
+		})
+
+		It("should find a segment", func() {
+			polytope := NewtonPolytope(expandString("(x + y)^3"))
+
+			Expect(polytope.Dimension).To(Equal(1))
+			Expect(polytope.Vertices).To(Equal([][]int{{0, 3}, {3, 0}}))
+			Expect(polytope.Facets).To(Equal([][]int{{0}, {1}}))
+		})
+
+		It("should find a polygon in space", func() {
+			polytope := NewtonPolytope(expandString("(x + y + z)^2"))
+
+			Expect(polytope.Dimension).To(Equal(2))
+			Expect(polytope.Vertices).To(ConsistOf([]int{2, 0, 0}, []int{0, 2, 0}, []int{0, 0, 2}))
+			Expect(polytope.Facets).To(HaveLen(3))
+		})
+
+		It("should find a solid", func() {
+			polytope := NewtonPolytope(expandString("(1 + x + x^2) (1 + y + y^2) (1 + z + z^2)"))
+
+			Expect(polytope.Dimension).To(Equal(3))
+			Expect(polytope.Vertices).To(HaveLen(8))
+			Expect(polytope.Facets).To(HaveLen(6))
+
+			for _, facet := range polytope.Facets {
+				Expect(facet).To(HaveLen(4))
+			}
+
+			// the facet x = 0 is counterclockwise seen from negative x
+			Expect(polytope.Facets[0]).To(Equal([]int{0, 1, 3, 2}))
+			Expect(polytope.Vertices[1]).To(Equal([]int{0, 0, 2}))
+		})
+
+		It("should find a tetrahedron", func() {
+			polytope := NewtonPolytope(expandString("(1 + x + y + z)^3"))
+
+			Expect(polytope.Vertices).To(Equal([][]int{{0, 0, 0}, {0, 0, 3}, {0, 3, 0}, {3, 0, 0}}))
+			Expect(polytope.Facets).To(Equal([][]int{{0, 1, 2}, {0, 2, 3}, {0, 3, 1}, {1, 3, 2}}))
+		})
+
+		It("should find vertices only in more variables", func() {
+			polytope := NewtonPolytope(expandString("(1 + a + b + c + d)^2"))
+
+			Expect(polytope.Dimension).To(Equal(4))
+			Expect(polytope.Vertices).To(HaveLen(5))
+			Expect(polytope.Facets).To(BeNil())
+		})
+
+		It("should stop when the context is done", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+
+			_, err := NewtonPolytopeContext(ctx, expandString("(1 + a + b + c + d)^6"), Limits{})
+			Expect(err).To(Equal(context.Canceled))
+		})
+	})
+})
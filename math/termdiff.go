@@ -0,0 +1,74 @@
+// Copyright (c) 2017 Przemysław Dobrowolski
+//
+// This file is part of the math-mod, a package for symbolic manipulation
+// of large algebraic expressions.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package math
+
+import (
+	"math/big"
+)
+
+// TermDiff is the difference of two polynomials term by term. Terms are in
+// descending monomial order.
+type TermDiff struct {
+	OnlyA   []Term
+	OnlyB   []Term
+	Changed []TermChange
+	Common  int // number of terms equal in both
+}
+
+// TermChange is a monomial with different coefficients in two polynomials.
+type TermChange struct {
+	Mono Monomial
+	A, B *big.Rat
+}
+
+// DiffTerms compares the polynomials term by term.
+func DiffTerms(a, b Poly, order Order) TermDiff {
+	var diff TermDiff
+
+	for _, term := range a.TermsIn(order) {
+		other, ok := b.terms[term.Mono.String()]
+
+		switch {
+		case !ok:
+			diff.OnlyA = append(diff.OnlyA, term)
+		case term.Coeff.Cmp(other.Coeff) != 0:
+			diff.Changed = append(diff.Changed, TermChange{Mono: term.Mono, A: term.Coeff, B: other.Coeff})
+		default:
+			diff.Common++
+		}
+	}
+
+	for _, term := range b.TermsIn(order) {
+		if _, ok := a.terms[term.Mono.String()]; !ok {
+			diff.OnlyB = append(diff.OnlyB, term)
+		}
+	}
+
+	return diff
+}
+
+// Equal reports whether the polynomials have no different terms.
+func (diff TermDiff) Equal() bool {
+	return len(diff.OnlyA) == 0 && len(diff.OnlyB) == 0 && len(diff.Changed) == 0
+}
@@ -0,0 +1,55 @@
+// Copyright (c) 2017 Przemysław Dobrowolski
+//
+// This file is part of the math-mod, a package for symbolic manipulation
+// of large algebraic expressions.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package math
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("TermDiff Object", func() {
+	Context("when two polynomials are compared", func() {
+		It("should classify the terms", func() {
+			diff := DiffTerms(expandString("(x + y)^2 + 3"), expandString("x^2 + 3 x y + y^2 + z"), Grlex)
+
+			Expect(diff.OnlyA).To(HaveLen(1))
+			Expect(diff.OnlyA[0].Expr().String()).To(Equal("3"))
+			Expect(diff.OnlyB).To(HaveLen(1))
+			Expect(diff.OnlyB[0].Expr().String()).To(Equal("z"))
+			Expect(diff.Changed).To(HaveLen(1))
+			Expect(diff.Changed[0].Mono.String()).To(Equal("x*y"))
+			Expect(diff.Changed[0].A.RatString()).To(Equal("2"))
+			Expect(diff.Changed[0].B.RatString()).To(Equal("3"))
+			Expect(diff.Common).To(Equal(2))
+			Expect(diff.Equal()).To(BeFalse())
+		})
+
+		It("should find equal expansions equal", func() {
+			diff := DiffTerms(expandString("(x - 1)(x + 1)"), expandString("x^2 - 1"), Lex)
+
+			Expect(diff.Equal()).To(BeTrue())
+			Expect(diff.Common).To(Equal(2))
+		})
+	})
+})
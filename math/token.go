@@ -24,7 +24,7 @@
 package math
 
 import (
-	"fmt"
+	"math/big"
 )
 
 const (
@@ -43,6 +43,12 @@ const (
 	// brackets
 	KindOpen
 	KindClose
+
+	// function call
+	KindFunc
+
+	// division, added last to keep the values of the kinds above
+	KindDiv
 )
 
 type Kind int
@@ -53,10 +59,22 @@ var OperProps = map[Kind]struct {
 }{
 	KindPow:   {4, true},
 	KindMul:   {3, false},
+	KindDiv:   {3, false},
 	KindPlus:  {2, false},
 	KindMinus: {2, false},
 }
 
+// Functions lists the names that are parsed as function calls rather than
+// variables.
+var Functions = map[string]bool{
+	"sin":  true,
+	"cos":  true,
+	"tan":  true,
+	"exp":  true,
+	"log":  true,
+	"sqrt": true,
+}
+
 type Token struct {
 	Kind  Kind
 	Value interface{}
@@ -69,6 +87,17 @@ func NewInt(value int64) Token {
 	}
 }
 
+func NewBigInt(value *big.Int) Token {
+	if value.IsInt64() {
+		return NewInt(value.Int64())
+	}
+
+	return Token{
+		Kind:  KindInt,
+		Value: new(big.Int).Set(value),
+	}
+}
+
 func NewVar(value string) Token {
 	return Token{
 		Kind:  KindVar,
@@ -76,6 +105,13 @@ func NewVar(value string) Token {
 	}
 }
 
+func NewFunc(name string) Token {
+	return Token{
+		Kind:  KindFunc,
+		Value: name,
+	}
+}
+
 func NewPlus() Token {
 	return Token{Kind: KindPlus}
 }
@@ -88,6 +124,10 @@ func NewMul() Token {
 	return Token{Kind: KindMul}
 }
 
+func NewDiv() Token {
+	return Token{Kind: KindDiv}
+}
+
 func NewPow() Token {
 	return Token{Kind: KindPow}
 }
@@ -100,11 +140,24 @@ func NewClose() Token {
 	return Token{Kind: KindClose}
 }
 
+// BigInt returns the value of an integer token regardless of whether it is
+// stored as int64 or as *big.Int.
+func (token Token) BigInt() *big.Int {
+	switch value := token.Value.(type) {
+	case int64:
+		return big.NewInt(value)
+	case *big.Int:
+		return new(big.Int).Set(value)
+	}
+
+	panic("invalid integer token value")
+}
+
 func (token Token) String() string {
 	switch token.Kind {
 	case KindInt:
-		return fmt.Sprintf("%d", token.Value.(int64))
-	case KindVar:
+		return token.BigInt().String()
+	case KindVar, KindFunc:
 		return token.Value.(string)
 	case KindPlus:
 		return "+"
@@ -112,6 +165,8 @@ func (token Token) String() string {
 		return "-"
 	case KindMul:
 		return "*"
+	case KindDiv:
+		return "/"
 	case KindPow:
 		return "^"
 	case KindOpen:
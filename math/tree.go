@@ -0,0 +1,196 @@
+// Copyright (c) 2017 Przemysław Dobrowolski
+//
+// This file is part of the math-mod, a package for symbolic manipulation
+// of large algebraic expressions.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package math
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Expr is a node of an expression tree. Integers and variables are leaves,
+// operators and function calls keep their operands in Args.
+type Expr struct {
+	Token Token
+	Args  []*Expr
+}
+
+func NewLeaf(token Token) *Expr {
+	return &Expr{Token: token}
+}
+
+func NewNode(token Token, args ...*Expr) *Expr {
+	return &Expr{
+		Token: token,
+		Args:  args,
+	}
+}
+
+func ToTree(postfix Tokens) (*Expr, error) {
+	var stack []*Expr
+
+	for _, token := range postfix {
+		switch token.Kind {
+		case KindInt, KindVar:
+			stack = append(stack, NewLeaf(token))
+		case KindPlus, KindMinus, KindMul, KindDiv, KindPow:
+			if len(stack) < 2 {
+				return nil, fmt.Errorf("missing operand for operator: %v", token)
+			}
+			left, right := stack[len(stack)-2], stack[len(stack)-1]
+			stack = append(stack[:len(stack)-2], NewNode(token, left, right))
+		case KindFunc:
+			if len(stack) < 1 {
+				return nil, fmt.Errorf("missing argument for function: %v", token)
+			}
+			stack[len(stack)-1] = NewNode(token, stack[len(stack)-1])
+		default:
+			return nil, fmt.Errorf("unexpected token: %v", token)
+		}
+	}
+
+	switch len(stack) {
+	case 0:
+		return nil, fmt.Errorf("empty expression")
+	case 1:
+		return stack[0], nil
+	default:
+		return nil, fmt.Errorf("missing operator between operands")
+	}
+}
+
+func (expr *Expr) IsLeaf() bool {
+	return len(expr.Args) == 0
+}
+
+func (expr *Expr) Depth() int {
+	depth := 0
+
+	for _, arg := range expr.Args {
+		if d := arg.Depth(); d > depth {
+			depth = d
+		}
+	}
+	return depth + 1
+}
+
+// prec returns the binding strength of the node when it is printed in infix.
+// Negative integers print with a leading sign and so bind like a product.
+func (expr *Expr) prec() int {
+	if prop, isOper := OperProps[expr.Token.Kind]; isOper {
+		return prop.prec
+	}
+	if expr.Token.Kind == KindInt && expr.Token.BigInt().Sign() < 0 {
+		return OperProps[KindMul].prec
+	}
+	return OperProps[KindPow].prec + 1
+}
+
+func (expr *Expr) Infix() (infix Tokens) {
+	if expr.IsLeaf() {
+		return Tokens{expr.Token}
+	}
+
+	if expr.Token.Kind == KindFunc {
+		infix = Tokens{expr.Token}
+		return append(infix, expr.Args[0].operand(true)...)
+	}
+
+	prop := OperProps[expr.Token.Kind]
+	left, right := expr.Args[0], expr.Args[1]
+
+	infix = append(infix, left.operand(left.prec() < prop.prec || left.prec() == prop.prec && prop.rightAssoc)...)
+	infix = append(infix, expr.Token)
+	infix = append(infix, right.operand(right.prec() < prop.prec || right.prec() == prop.prec && !prop.rightAssoc)...)
+	return
+}
+
+func (expr *Expr) operand(bracket bool) Tokens {
+	if !bracket {
+		return expr.Infix()
+	}
+
+	infix := Tokens{NewOpen()}
+	infix = append(infix, expr.Infix()...)
+	return append(infix, NewClose())
+}
+
+func (expr *Expr) Postfix() (postfix Tokens) {
+	for _, arg := range expr.Args {
+		postfix = append(postfix, arg.Postfix()...)
+	}
+	return append(postfix, expr.Token)
+}
+
+// Equal reports whether both trees have the same structure and leaves.
+func (expr *Expr) Equal(other *Expr) bool {
+	if expr.Token.Kind != other.Token.Kind || len(expr.Args) != len(other.Args) {
+		return false
+	}
+
+	switch expr.Token.Kind {
+	case KindInt:
+		if expr.Token.BigInt().Cmp(other.Token.BigInt()) != 0 {
+			return false
+		}
+	case KindVar, KindFunc:
+		if expr.Token.Value.(string) != other.Token.Value.(string) {
+			return false
+		}
+	}
+
+	for i, arg := range expr.Args {
+		if !arg.Equal(other.Args[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// Subst returns a copy of the tree with every variable found in values
+// replaced by its expression. Unchanged subtrees are shared with the original.
+func (expr *Expr) Subst(values map[string]*Expr) *Expr {
+	if expr.IsLeaf() {
+		if expr.Token.Kind == KindVar {
+			if value, ok := values[expr.Token.Value.(string)]; ok {
+				return value
+			}
+		}
+		return expr
+	}
+
+	args := make([]*Expr, len(expr.Args))
+	for i, arg := range expr.Args {
+		args[i] = arg.Subst(values)
+	}
+	return NewNode(expr.Token, args...)
+}
+
+func (expr *Expr) String() string {
+	var parts []string
+
+	for _, token := range expr.Infix() {
+		parts = append(parts, token.String())
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,79 @@
+// Copyright (c) 2017 Przemysław Dobrowolski
+//
+// This file is part of the math-mod, a package for symbolic manipulation
+// of large algebraic expressions.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package math
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func parseTree(infix string) *Expr {
+	tokens, err := ParseInfixString(infix)
+	Expect(err).ShouldNot(HaveOccurred())
+
+	expr, err := ToTree(ToPostfix(ImplicitOperMul(tokens)))
+	Expect(err).ShouldNot(HaveOccurred())
+	return expr
+}
+
+var _ = Describe("Tree Object", func() {
+	Context("when postfix is converted to a tree", func() {
+		It("should keep the operands in order", func() {
+			expr := parseTree("x - y")
+			Expect(expr).To(Equal(NewNode(NewMinus(), NewLeaf(NewVar("x")), NewLeaf(NewVar("y")))))
+		})
+	})
+
+	Context("when an operator is missing an operand", func() {
+		It("should fail", func() {
+			_, err := ToTree(Tokens{NewInt(1), NewPlus()})
+			Expect(err).Should(HaveOccurred())
+		})
+	})
+
+	Context("when a tree is printed in infix", func() {
+		It("should bracket only where needed", func() {
+			Expect(parseTree("(x - (y - z)) * (a + b) ^ 2 ^ 3").String()).To(Equal("( x - ( y - z ) ) * ( a + b ) ^ 2 ^ 3"))
+			Expect(parseTree("(x ^ 2) ^ 3 + (x * y) * z").String()).To(Equal("( x ^ 2 ) ^ 3 + x * y * z"))
+		})
+	})
+
+	Context("when a tree is converted back to postfix", func() {
+		It("should match the postfix it was built from", func() {
+			infix, err := ParseInfixString("3 + 4 * 2 - ( 1 - 5 ) ^ 2 ^ 3")
+			Expect(err).ShouldNot(HaveOccurred())
+
+			postfix := ToPostfix(infix)
+			expr, err := ToTree(postfix)
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(expr.Postfix()).To(Equal(postfix))
+		})
+	})
+
+	Context("when a variable is substituted", func() {
+		It("should replace every occurrence", func() {
+			Expect(parseTree("x^2 + x*y").Subst(map[string]*Expr{"x": parseTree("a + 1")}).String()).To(Equal("( a + 1 ) ^ 2 + ( a + 1 ) * y"))
+		})
+	})
+})
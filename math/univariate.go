@@ -0,0 +1,213 @@
+// Copyright (c) 2017 Przemysław Dobrowolski
+//
+// This file is part of the math-mod, a package for symbolic manipulation
+// of large algebraic expressions.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package math
+
+import (
+	"math/big"
+)
+
+// dense is a univariate polynomial over Q stored as coefficients indexed by
+// the exponent, without trailing zeros; the zero polynomial is empty.
+type dense []*big.Rat
+
+// toDense converts a polynomial in the variable alone; it reports false if
+// other variables occur.
+func toDense(poly Poly, name string) (dense, bool) {
+	result := make(dense, poly.DegreeIn(name)+1)
+
+	for i := range result {
+		result[i] = new(big.Rat)
+	}
+
+	for _, term := range poly.terms {
+		if len(term.Mono) > 1 || len(term.Mono) == 1 && term.Mono[0].Var != name {
+			return nil, false
+		}
+		result[term.Mono.Exp(name)].Set(term.Coeff)
+	}
+
+	return result.trim(), true
+}
+
+func (d dense) poly(name string) Poly {
+	result := NewPolyRing(Rationals)
+
+	for i, coeff := range d {
+		var mono Monomial
+		if i > 0 {
+			mono = Monomial{{Var: name, Exp: i}}
+		}
+		result.addTerm(coeff, mono)
+	}
+	return result
+}
+
+func (d dense) trim() dense {
+	for len(d) > 0 && d[len(d)-1].Sign() == 0 {
+		d = d[:len(d)-1]
+	}
+	return d
+}
+
+func (d dense) degree() int {
+	return len(d) - 1
+}
+
+func (d dense) lead() *big.Rat {
+	return d[len(d)-1]
+}
+
+func (d dense) add(other dense) dense {
+	if len(d) < len(other) {
+		d, other = other, d
+	}
+
+	result := make(dense, len(d))
+
+	for i := range d {
+		result[i] = new(big.Rat).Set(d[i])
+		if i < len(other) {
+			result[i].Add(result[i], other[i])
+		}
+	}
+	return result.trim()
+}
+
+func (d dense) sub(other dense) dense {
+	return d.add(other.scale(big.NewRat(-1, 1)))
+}
+
+func (d dense) scale(factor *big.Rat) dense {
+	result := make(dense, len(d))
+
+	for i, coeff := range d {
+		result[i] = new(big.Rat).Mul(coeff, factor)
+	}
+	return result.trim()
+}
+
+func (d dense) mul(other dense) dense {
+	if len(d) == 0 || len(other) == 0 {
+		return nil
+	}
+
+	result := make(dense, len(d)+len(other)-1)
+
+	for i := range result {
+		result[i] = new(big.Rat)
+	}
+
+	product := new(big.Rat)
+
+	for i, a := range d {
+		for j, b := range other {
+			result[i+j].Add(result[i+j], product.Mul(a, b))
+		}
+	}
+	return result.trim()
+}
+
+// divMod divides by a non-zero polynomial with remainder.
+func (d dense) divMod(divisor dense) (quo, rem dense) {
+	rem = d.scale(big.NewRat(1, 1))
+
+	if len(rem) < len(divisor) {
+		return nil, rem
+	}
+
+	quo = make(dense, len(rem)-len(divisor)+1)
+	inv := new(big.Rat).Inv(divisor.lead())
+
+	for i := len(quo) - 1; i >= 0; i-- {
+		quo[i] = new(big.Rat)
+
+		if len(rem) != i+len(divisor) {
+			continue
+		}
+
+		quo[i].Mul(rem.lead(), inv)
+
+		for j, coeff := range divisor {
+			rem[i+j].Sub(rem[i+j], new(big.Rat).Mul(quo[i], coeff))
+		}
+		rem = rem.trim()
+	}
+	return quo.trim(), rem
+}
+
+func (d dense) monic() dense {
+	if len(d) == 0 {
+		return d
+	}
+	return d.scale(new(big.Rat).Inv(d.lead()))
+}
+
+// gcd returns the monic greatest common divisor.
+func (d dense) gcd(other dense) dense {
+	for len(other) > 0 {
+		_, rem := d.divMod(other)
+		d, other = other, rem
+	}
+	return d.monic()
+}
+
+// invMod returns the inverse modulo a polynomial coprime to d, of degree
+// lower than the modulus.
+func (d dense) invMod(modulus dense) dense {
+	// extended Euclid keeping only the cofactor of d
+	a, b := modulus, d
+	s, t := dense(nil), dense{big.NewRat(1, 1)}
+
+	for len(b) > 0 {
+		quo, rem := a.divMod(b)
+		a, b = b, rem
+		s, t = t, s.sub(quo.mul(t))
+	}
+
+	_, s = s.scale(new(big.Rat).Inv(a.lead())).divMod(modulus)
+	return s
+}
+
+func (d dense) diff() dense {
+	if len(d) == 0 {
+		return d
+	}
+
+	result := make(dense, len(d)-1)
+
+	for i := range result {
+		result[i] = new(big.Rat).Mul(d[i+1], big.NewRat(int64(i+1), 1))
+	}
+	return result.trim()
+}
+
+func (d dense) eval(value *big.Rat) *big.Rat {
+	result := new(big.Rat)
+
+	for i := len(d) - 1; i >= 0; i-- {
+		result.Mul(result, value)
+		result.Add(result, d[i])
+	}
+	return result
+}
@@ -0,0 +1,243 @@
+// Copyright (c) 2017 Przemysław Dobrowolski
+//
+// This file is part of the math-mod, a package for symbolic manipulation
+// of large algebraic expressions.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+// Package mm embeds math-mod in Go programs. An expression is parsed once
+// and then expanded, evaluated or printed, with functional options taking
+// the place of command line flags:
+//
+//	expr, err := mm.Parse("(x + y)^2", mm.WithLimits(math.Limits{MaxTerms: 1000}))
+//	expanded, err := expr.Expand()
+//	fmt.Println(expanded)
+//
+// Expressions are immutable and safe for concurrent use.
+package mm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/pdobrowo/mm/math"
+)
+
+type options struct {
+	ctx     context.Context
+	mul     math.MulPolicy
+	limits  math.Limits
+	modulus uint64
+	order   math.Order
+	jobs    int
+}
+
+// Option configures parsing and the transformations of the parsed
+// expression.
+type Option func(*options)
+
+// WithContext makes transformations give up when the context is done.
+func WithContext(ctx context.Context) Option {
+	return func(opts *options) {
+		opts.ctx = ctx
+	}
+}
+
+// WithImplicitMul sets where multiplication may be implicit, permissive by
+// default.
+func WithImplicitMul(policy math.MulPolicy) Option {
+	return func(opts *options) {
+		opts.mul = policy
+	}
+}
+
+// WithLimits bounds the resources of transformations, which fail with
+// *math.LimitError when exceeding them.
+func WithLimits(limits math.Limits) Option {
+	return func(opts *options) {
+		opts.limits = limits
+	}
+}
+
+// WithModulus makes expansion take coefficients modulo the prime p.
+func WithModulus(p uint64) Option {
+	return func(opts *options) {
+		opts.modulus = p
+	}
+}
+
+// WithOrder sets the monomial order of expanded terms, grlex by default.
+func WithOrder(order math.Order) Option {
+	return func(opts *options) {
+		opts.order = order
+	}
+}
+
+// WithJobs sets the number of goroutines multiplying large polynomials.
+func WithJobs(jobs int) Option {
+	return func(opts *options) {
+		opts.jobs = jobs
+	}
+}
+
+// Expr is a parsed expression with the options it was parsed with.
+type Expr struct {
+	tree *math.Expr
+	opts options
+	ring math.Ring
+}
+
+// Parse parses an infix expression. Syntax errors are reported as
+// *math.ParseError.
+func Parse(src string, opts ...Option) (*Expr, error) {
+	return ParseReader(strings.NewReader(src), opts...)
+}
+
+func ParseReader(reader io.Reader, opts ...Option) (*Expr, error) {
+	expr := &Expr{
+		opts: options{
+			ctx:   context.Background(),
+			order: math.Grlex,
+			jobs:  1,
+		},
+		ring: math.Integers,
+	}
+
+	for _, opt := range opts {
+		opt(&expr.opts)
+	}
+
+	if expr.opts.jobs < 1 {
+		return nil, fmt.Errorf("invalid number of jobs: %d", expr.opts.jobs)
+	}
+
+	if expr.opts.modulus != 0 {
+		ring, err := math.NewModular(expr.opts.modulus)
+
+		if err != nil {
+			return nil, err
+		}
+
+		expr.ring = ring
+	}
+
+	parser := &math.Parser{Mul: expr.opts.mul}
+	tree, err := parser.Parse(reader)
+
+	if err != nil {
+		return nil, err
+	}
+
+	expr.tree = tree
+	return expr, nil
+}
+
+// Tree returns the expression tree, which must not be modified.
+func (expr *Expr) Tree() *math.Expr {
+	return expr.tree
+}
+
+// with returns an expression with the tree and the options of expr.
+func (expr *Expr) with(tree *math.Expr) *Expr {
+	return &Expr{tree: tree, opts: expr.opts, ring: expr.ring}
+}
+
+// Poly expands the expression into a polynomial.
+func (expr *Expr) Poly() (math.Poly, error) {
+	return math.ExpandRing(expr.opts.ctx, expr.tree, expr.ring, expr.opts.limits, expr.opts.jobs)
+}
+
+// Expand returns the expanded expression with its terms in the monomial
+// order.
+func (expr *Expr) Expand() (*Expr, error) {
+	poly, err := expr.Poly()
+
+	if err != nil {
+		return nil, err
+	}
+
+	return expr.with(poly.ExprIn(expr.opts.order)), nil
+}
+
+// Eval computes the value of the expression in floating point.
+func (expr *Expr) Eval(values map[string]float64) (float64, error) {
+	return math.Eval(expr.tree, values)
+}
+
+// Subst returns the expression with variables replaced by expressions.
+func (expr *Expr) Subst(values map[string]*Expr) *Expr {
+	trees := make(map[string]*math.Expr, len(values))
+	for name, value := range values {
+		trees[name] = value.tree
+	}
+	return expr.with(expr.tree.Subst(trees))
+}
+
+func (expr *Expr) Stats() math.Stats {
+	return expr.tree.Stats()
+}
+
+// Format selects the notation an expression is printed in.
+type Format int
+
+const (
+	Infix Format = iota
+	Postfix
+	Prefix
+	Sexpr
+	Latex
+	JSON
+	Dot
+)
+
+// Format prints the expression in the notation.
+func (expr *Expr) Format(format Format) (string, error) {
+	switch format {
+	case Infix:
+		return expr.tree.Infix().Join(), nil
+	case Postfix:
+		return expr.tree.Postfix().Join(), nil
+	case Prefix:
+		return expr.tree.Prefix().Join(), nil
+	case Sexpr:
+		return expr.tree.Sexpr(), nil
+	case Latex:
+		return expr.tree.Latex(), nil
+	case JSON:
+		data, err := json.Marshal(expr.tree)
+		return string(data), err
+	case Dot:
+		return expr.tree.Dot(math.DotOptions{}), nil
+	}
+
+	return "", fmt.Errorf("unknown format: %d", format)
+}
+
+// Postfix prints the expression in postfix (RPN) notation.
+func (expr *Expr) Postfix() string {
+	return expr.tree.Postfix().Join()
+}
+
+// String prints the expression in infix notation.
+func (expr *Expr) String() string {
+	return expr.tree.String()
+}
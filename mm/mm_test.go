@@ -0,0 +1,107 @@
+// Copyright (c) 2017 Przemysław Dobrowolski
+//
+// This file is part of the math-mod, a package for symbolic manipulation
+// of large algebraic expressions.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package mm
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pdobrowo/mm/math"
+)
+
+var _ = Describe("Expr Object", func() {
+	Context("when an expression is parsed", func() {
+		It("should print it", func() {
+			expr, err := Parse("(x + y)^2 / 2")
+			Expect(err).ShouldNot(HaveOccurred())
+
+			Expect(expr.String()).To(Equal("( x + y ) ^ 2 / 2"))
+			Expect(expr.Postfix()).To(Equal("x y + 2 ^ 2 /"))
+			Expect(expr.Format(Prefix)).To(Equal("/ ^ + x y 2 2"))
+			Expect(expr.Format(Sexpr)).To(Equal("(/ (^ (+ x y) 2) 2)"))
+			Expect(expr.Format(Latex)).To(Equal(`\frac{\left(x + y\right)^{2}}{2}`))
+			Expect(expr.Stats().Nodes).To(Equal(7))
+
+			_, err = expr.Format(Format(-1))
+			Expect(err).Should(HaveOccurred())
+		})
+
+		It("should apply the implicit multiplication policy", func() {
+			_, err := Parse("x y", WithImplicitMul(math.MulStrict))
+			Expect(err).To(BeAssignableToTypeOf(&math.ParseError{}))
+
+			_, err = Parse("2 3")
+			Expect(err).Should(HaveOccurred())
+		})
+	})
+
+	Context("when an expression is transformed", func() {
+		It("should expand and evaluate it", func() {
+			expr, err := Parse("(x - y)(x + y) + 3 x", WithOrder(math.Lex))
+			Expect(err).ShouldNot(HaveOccurred())
+
+			expanded, err := expr.Expand()
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(expanded.String()).To(Equal("x ^ 2 + 3 * x - y ^ 2"))
+
+			value, err := expanded.Eval(map[string]float64{"x": 2, "y": 1})
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(value).To(Equal(9.0))
+
+			two, err := Parse("2")
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(expr.Subst(map[string]*Expr{"y": two}).String()).To(Equal("( x - 2 ) * ( x + 2 ) + 3 * x"))
+		})
+
+		It("should expand modulo a prime", func() {
+			expr, err := Parse("(x + 1)^5", WithModulus(5), WithJobs(2))
+			Expect(err).ShouldNot(HaveOccurred())
+
+			expanded, err := expr.Expand()
+			Expect(err).ShouldNot(HaveOccurred())
+			Expect(expanded.String()).To(Equal("x ^ 5 + 1"))
+
+			_, err = Parse("x", WithModulus(4))
+			Expect(err).Should(HaveOccurred())
+		})
+
+		It("should respect limits and contexts", func() {
+			expr, err := Parse("(a + b + c)^10", WithLimits(math.Limits{MaxTerms: 10}))
+			Expect(err).ShouldNot(HaveOccurred())
+
+			_, err = expr.Expand()
+			Expect(err).To(BeAssignableToTypeOf(&math.LimitError{}))
+
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+
+			expr, err = Parse("(a + b + c)^30", WithContext(ctx))
+			Expect(err).ShouldNot(HaveOccurred())
+
+			_, err = expr.Expand()
+			Expect(err).To(MatchError(context.Canceled))
+		})
+	})
+})